@@ -0,0 +1,190 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	"sync"
+	"time"
+)
+
+type AdaptiveConcurrency struct {
+	Min           int
+	Max           int
+	TargetLatency time.Duration
+	MaxErrorRate  float64
+	Backoff       float64
+	Interval      time.Duration
+}
+
+func (c AdaptiveConcurrency) withDefaults() AdaptiveConcurrency {
+	if c.Min == 0 {
+		c.Min = 1
+	}
+	if c.MaxErrorRate == 0 {
+		c.MaxErrorRate = 0.1
+	}
+	if c.Backoff == 0 {
+		c.Backoff = 0.75
+	}
+	if c.Interval == 0 {
+		c.Interval = time.Second
+	}
+	return c
+}
+
+func (c AdaptiveConcurrency) validate() error {
+	if c.Min < 1 {
+		return fmt.Errorf("adaptive min concurrency must be positive, got %d", c.Min)
+	}
+	if c.Max < c.Min {
+		return fmt.Errorf("adaptive max concurrency must be at least %d, got %d", c.Min, c.Max)
+	}
+	if c.TargetLatency < 0 {
+		return fmt.Errorf("adaptive target latency cannot be negative, got %s", c.TargetLatency)
+	}
+	if c.MaxErrorRate <= 0 || c.MaxErrorRate > 1 {
+		return fmt.Errorf("adaptive max error rate must be in (0, 1], got %v", c.MaxErrorRate)
+	}
+	if c.Backoff <= 0 || c.Backoff >= 1 {
+		return fmt.Errorf("adaptive backoff must be in (0, 1), got %v", c.Backoff)
+	}
+	if c.Interval <= 0 {
+		return fmt.Errorf("adaptive interval must be positive, got %s", c.Interval)
+	}
+	return nil
+}
+
+type adaptiveStats struct {
+	completed int
+	failed    int
+	latency   time.Duration
+}
+
+type adaptiveLimiter struct {
+	mutex  *sync.Mutex
+	config AdaptiveConcurrency
+	limit  int
+	parked int
+	stats  adaptiveStats
+}
+
+func newAdaptiveLimiter(config AdaptiveConcurrency, initial int) *adaptiveLimiter {
+	limit := initial
+	if limit < config.Min {
+		limit = config.Min
+	}
+	if limit > config.Max {
+		limit = config.Max
+	}
+	return &adaptiveLimiter{mutex: &sync.Mutex{}, config: config, limit: limit}
+}
+
+func (l *adaptiveLimiter) observe(latency time.Duration, err error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.stats.completed++
+	l.stats.latency += latency
+	if err != nil {
+		l.stats.failed++
+	}
+}
+
+func (l *adaptiveLimiter) collect() adaptiveStats {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	stats := l.stats
+	l.stats = adaptiveStats{}
+	return stats
+}
+
+func (l *adaptiveLimiter) next(limit int, stats adaptiveStats, queued, inUse int) int {
+	if stats.completed > 0 {
+		errorRate := float64(stats.failed) / float64(stats.completed)
+		average := stats.latency / time.Duration(stats.completed)
+		if errorRate > l.config.MaxErrorRate || (l.config.TargetLatency > 0 && average > l.config.TargetLatency) {
+			return int(math.Max(float64(l.config.Min), math.Floor(float64(limit)*l.config.Backoff)))
+		}
+	}
+	if queued > 0 && inUse >= limit && limit < l.config.Max {
+		return limit + 1
+	}
+	return limit
+}
+
+func (eb *EventBus) observeHandler(latency time.Duration, err error) {
+	if eb.adaptive != nil {
+		eb.adaptive.observe(latency, err)
+	}
+}
+
+func (eb *EventBus) queuedEvents() int {
+	queued := 0
+	for _, priority := range priorities {
+		queued += len(eb.requestLanes[priority]) + len(eb.responseLanes[priority])
+	}
+	return queued
+}
+
+func (eb *EventBus) ConcurrencyLimit() int {
+	if eb.adaptive == nil {
+		return cap(eb.workerPool)
+	}
+	eb.adaptive.mutex.Lock()
+	defer eb.adaptive.mutex.Unlock()
+	return eb.adaptive.limit
+}
+
+func (eb *EventBus) runAdaptive() {
+	if eb.adaptive == nil {
+		return
+	}
+	ctx := context.Background()
+	ticker := time.NewTicker(eb.adaptive.config.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-eb.stopChannel:
+			return
+		case <-ticker.C:
+			eb.adaptive.mutex.Lock()
+			limit, parked := eb.adaptive.limit, eb.adaptive.parked
+			eb.adaptive.mutex.Unlock()
+			inUse := len(eb.workerPool) - parked
+			next := eb.adaptive.next(limit, eb.adaptive.collect(), eb.queuedEvents(), inUse)
+			if next != limit {
+				eb.adaptConcurrency(ctx, next)
+			}
+		}
+	}
+}
+
+func (eb *EventBus) adaptConcurrency(ctx context.Context, limit int) {
+	eb.adaptive.mutex.Lock()
+	target := cap(eb.workerPool) - limit
+	parked := eb.adaptive.parked
+	eb.adaptive.limit = limit
+	eb.adaptive.mutex.Unlock()
+
+	for ; parked > target; parked-- {
+		<-eb.workerPool
+		eb.setParked(parked - 1)
+	}
+	for ; parked < target; parked++ {
+		select {
+		case eb.workerPool <- struct{}{}:
+			eb.setParked(parked + 1)
+		case <-eb.stopChannel:
+			return
+		}
+	}
+	eb.concurrencyLimit.Record(ctx, int64(limit), eb.metricAttrs())
+	eb.logger.DebugContext(ctx, "worker pool limit adjusted", slog.Int("limit", limit))
+}
+
+func (eb *EventBus) setParked(parked int) {
+	eb.adaptive.mutex.Lock()
+	eb.adaptive.parked = parked
+	eb.adaptive.mutex.Unlock()
+}
@@ -0,0 +1,89 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveLimiterAIMD(t *testing.T) {
+	limiter := newAdaptiveLimiter(AdaptiveConcurrency{Min: 2, Max: 10, TargetLatency: 100 * time.Millisecond}.withDefaults(), 4)
+	assert.Equal(t, 4, limiter.limit)
+
+	healthy := adaptiveStats{completed: 10, failed: 0, latency: 10 * 50 * time.Millisecond}
+	assert.Equal(t, 5, limiter.next(4, healthy, 3, 4), "Com fila e pool saturado o limite deve crescer de forma aditiva")
+	assert.Equal(t, 4, limiter.next(4, healthy, 0, 4), "Sem fila o limite deve ser mantido")
+	assert.Equal(t, 4, limiter.next(4, healthy, 3, 2), "Com slots livres o limite deve ser mantido")
+	assert.Equal(t, 10, limiter.next(10, healthy, 3, 10), "O limite não deve passar do máximo")
+
+	failing := adaptiveStats{completed: 10, failed: 5, latency: 10 * 50 * time.Millisecond}
+	assert.Equal(t, 6, limiter.next(8, failing, 3, 8), "Erros devem reduzir o limite de forma multiplicativa")
+	slow := adaptiveStats{completed: 2, latency: 2 * time.Second}
+	assert.Equal(t, 2, limiter.next(2, slow, 3, 2), "O limite não deve ficar abaixo do mínimo")
+}
+
+func TestAdaptiveLimiterClampsInitialLimit(t *testing.T) {
+	config := AdaptiveConcurrency{Min: 2, Max: 4}.withDefaults()
+	assert.Equal(t, 4, newAdaptiveLimiter(config, 10).limit)
+	assert.Equal(t, 2, newAdaptiveLimiter(config, 1).limit)
+}
+
+func TestAdaptConcurrencyParksWorkerSlots(t *testing.T) {
+	eventBus, err := New(WithWorkerPoolSize(3), WithAdaptiveConcurrency(AdaptiveConcurrency{Min: 1, Max: 8}))
+	assert.NoError(t, err)
+	assert.Equal(t, 8, cap(eventBus.workerPool), "O pool deve comportar o máximo adaptativo")
+
+	eventBus.adaptConcurrency(context.Background(), 3)
+	assert.Equal(t, 5, len(eventBus.workerPool))
+	assert.Equal(t, 3, eventBus.ConcurrencyLimit())
+
+	eventBus.adaptConcurrency(context.Background(), 6)
+	assert.Equal(t, 2, len(eventBus.workerPool))
+	eventBus.adaptConcurrency(context.Background(), 1)
+	assert.Equal(t, 7, len(eventBus.workerPool))
+}
+
+func TestAdaptiveConcurrencyGrowsUnderLoadAndShrinksOnErrors(t *testing.T) {
+	eventBus, _ := New(
+		WithWorkerPoolSize(1),
+		WithTimeout(time.Minute),
+		WithAdaptiveConcurrency(AdaptiveConcurrency{Min: 1, Max: 4, Interval: 20 * time.Millisecond}),
+	)
+	release := make(chan struct{})
+	failing := make(chan bool, 1)
+	failing <- false
+	eventBus.Register([]*Event{{
+		Name: "work",
+		Handler: func(payload interface{}) (interface{}, error) {
+			<-release
+			fail := <-failing
+			failing <- fail
+			if fail {
+				return nil, errors.New("downstream unavailable")
+			}
+			return nil, nil
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	for i := 0; i < 20; i++ {
+		assert.NoError(t, eventBus.Publish("work", i))
+	}
+	assert.Eventually(t, func() bool { return eventBus.ConcurrencyLimit() == 4 }, 2*time.Second, 10*time.Millisecond, "O limite deve crescer com eventos enfileirados")
+
+	<-failing
+	failing <- true
+	close(release)
+	assert.Eventually(t, func() bool { return eventBus.ConcurrencyLimit() < 4 }, 2*time.Second, 10*time.Millisecond, "Erros devem reduzir o limite")
+}
+
+func TestWithAdaptiveConcurrencyValidates(t *testing.T) {
+	_, err := New(WithAdaptiveConcurrency(AdaptiveConcurrency{Min: 4, Max: 2}))
+	assert.EqualError(t, err, "adaptive max concurrency must be at least 4, got 2")
+	_, err = New(WithAdaptiveConcurrency(AdaptiveConcurrency{Max: 2, Backoff: 1.5}))
+	assert.EqualError(t, err, "adaptive backoff must be in (0, 1), got 1.5")
+}
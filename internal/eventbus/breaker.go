@@ -0,0 +1,312 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	HeaderBreakerRetries   = "circuit_retries"
+	HeaderDeadLetterEvent  = "dead_letter_event"
+	HeaderDeadLetterReason = "dead_letter_reason"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker open")
+
+type BreakerState int
+
+const (
+	BreakerClosed BreakerState = iota
+	BreakerOpen
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+type CircuitBreakerConfig struct {
+	FailureRatio     float64
+	MinRequests      int
+	Window           time.Duration
+	Cooldown         time.Duration
+	HalfOpenRequests int
+	MaxRetries       int
+	DeadLetter       string
+}
+
+func (c CircuitBreakerConfig) withDefaults() CircuitBreakerConfig {
+	if c.FailureRatio == 0 {
+		c.FailureRatio = 0.5
+	}
+	if c.MinRequests == 0 {
+		c.MinRequests = 10
+	}
+	if c.Window == 0 {
+		c.Window = time.Minute
+	}
+	if c.Cooldown == 0 {
+		c.Cooldown = 30 * time.Second
+	}
+	if c.HalfOpenRequests == 0 {
+		c.HalfOpenRequests = 1
+	}
+	return c
+}
+
+func (c CircuitBreakerConfig) validate(name string) error {
+	if c.FailureRatio <= 0 || c.FailureRatio > 1 {
+		return fmt.Errorf("failure ratio for event %s must be in (0, 1], got %v", name, c.FailureRatio)
+	}
+	if c.MinRequests < 1 {
+		return fmt.Errorf("min requests for event %s must be positive, got %d", name, c.MinRequests)
+	}
+	if c.Window <= 0 {
+		return fmt.Errorf("breaker window for event %s must be positive, got %s", name, c.Window)
+	}
+	if c.Cooldown <= 0 {
+		return fmt.Errorf("breaker cooldown for event %s must be positive, got %s", name, c.Cooldown)
+	}
+	if c.HalfOpenRequests < 1 {
+		return fmt.Errorf("half-open requests for event %s must be positive, got %d", name, c.HalfOpenRequests)
+	}
+	if c.MaxRetries < 0 {
+		return fmt.Errorf("max retries for event %s cannot be negative, got %d", name, c.MaxRetries)
+	}
+	return nil
+}
+
+type circuitBreaker struct {
+	mutex       *sync.Mutex
+	config      CircuitBreakerConfig
+	state       BreakerState
+	requests    int
+	failures    int
+	windowStart time.Time
+	openedAt    time.Time
+	probes      int
+	successes   int
+	now         func() time.Time
+}
+
+func newCircuitBreaker(config CircuitBreakerConfig) *circuitBreaker {
+	return &circuitBreaker{
+		mutex:       &sync.Mutex{},
+		config:      config,
+		windowStart: time.Now(),
+		now:         time.Now,
+	}
+}
+
+func (b *circuitBreaker) allow() (bool, BreakerState) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if b.state == BreakerOpen && !b.now().Before(b.openedAt.Add(b.config.Cooldown)) {
+		b.state = BreakerHalfOpen
+		b.probes = 0
+		b.successes = 0
+	}
+	switch b.state {
+	case BreakerOpen:
+		return false, b.state
+	case BreakerHalfOpen:
+		if b.probes >= b.config.HalfOpenRequests {
+			return false, b.state
+		}
+		b.probes++
+	}
+	return true, b.state
+}
+
+func (b *circuitBreaker) record(err error) (BreakerState, bool) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	previous := b.state
+	now := b.now()
+	switch b.state {
+	case BreakerClosed:
+		if now.Sub(b.windowStart) >= b.config.Window {
+			b.reset(now)
+		}
+		b.requests++
+		if err != nil {
+			b.failures++
+		}
+		if b.requests >= b.config.MinRequests && float64(b.failures)/float64(b.requests) >= b.config.FailureRatio {
+			b.trip(now)
+		}
+	case BreakerHalfOpen:
+		if err != nil {
+			b.trip(now)
+			break
+		}
+		b.successes++
+		if b.successes >= b.config.HalfOpenRequests {
+			b.state = BreakerClosed
+			b.reset(now)
+		}
+	}
+	return b.state, b.state != previous
+}
+
+func (b *circuitBreaker) release() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.state == BreakerHalfOpen && b.probes > 0 {
+		b.probes--
+	}
+}
+
+func (b *circuitBreaker) trip(now time.Time) {
+	b.state = BreakerOpen
+	b.openedAt = now
+}
+
+func (b *circuitBreaker) reset(now time.Time) {
+	b.requests = 0
+	b.failures = 0
+	b.windowStart = now
+}
+
+func (b *circuitBreaker) retryAt() time.Time {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.openedAt.Add(b.config.Cooldown)
+}
+
+func (eb *EventBus) BreakerState(name string) (BreakerState, bool) {
+	breaker, ok := eb.breakers[name]
+	if !ok {
+		return BreakerClosed, false
+	}
+	breaker.mutex.Lock()
+	defer breaker.mutex.Unlock()
+	return breaker.state, true
+}
+
+type breakerCallKey struct{}
+
+type breakerCall struct {
+	mutex   *sync.Mutex
+	name    string
+	breaker *circuitBreaker
+	pending int
+	ran     bool
+	err     error
+}
+
+func (c *breakerCall) settle(err error, ran bool) (bool, bool, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.pending--
+	if ran {
+		c.ran = true
+		if c.err == nil {
+			c.err = err
+		}
+	}
+	return c.pending == 0, c.ran, c.err
+}
+
+func (eb *EventBus) allowBreaker(ctx context.Context, span trace.Span, envelope *EventPayload, handlers int) (context.Context, bool) {
+	breaker, ok := eb.breakers[envelope.Name]
+	if !ok {
+		return ctx, true
+	}
+	allowed, state := breaker.allow()
+	span.SetAttributes(attribute.String("circuit_breaker.state", state.String()))
+	if !allowed {
+		eb.shortCircuit(ctx, envelope, breaker)
+		return ctx, false
+	}
+	call := &breakerCall{mutex: &sync.Mutex{}, name: envelope.Name, breaker: breaker, pending: handlers}
+	return context.WithValue(ctx, breakerCallKey{}, call), true
+}
+
+func (eb *EventBus) recordBreaker(ctx context.Context, envelope *EventPayload, err error) {
+	if !envelope.replayed() {
+		eb.settleBreaker(ctx, err, true)
+	}
+}
+
+func (eb *EventBus) releaseBreaker(ctx context.Context, envelope *EventPayload) {
+	if !envelope.replayed() {
+		eb.settleBreaker(ctx, nil, false)
+	}
+}
+
+func (eb *EventBus) settleBreaker(ctx context.Context, err error, ran bool) {
+	call, ok := ctx.Value(breakerCallKey{}).(*breakerCall)
+	if !ok {
+		return
+	}
+	done, ran, err := call.settle(err, ran)
+	if !done {
+		return
+	}
+	if !ran {
+		call.breaker.release()
+		return
+	}
+	state, changed := call.breaker.record(err)
+	trace.SpanFromContext(ctx).SetAttributes(attribute.String("circuit_breaker.state", state.String()))
+	if changed {
+		eb.breakerState.Record(ctx, int64(state), eb.metricAttrs(attribute.String("event_name", call.name)))
+		eb.logger.WarnContext(ctx, "circuit breaker state changed", slog.String("event_name", call.name), slog.String("state", state.String()))
+	}
+}
+
+func (eb *EventBus) shortCircuit(ctx context.Context, envelope *EventPayload, breaker *circuitBreaker) {
+	retries, _ := strconv.Atoi(envelope.Headers[HeaderBreakerRetries])
+	switch {
+	case retries < breaker.config.MaxRetries:
+		retry := envelope.clone()
+		WithHeader(HeaderBreakerRetries, strconv.Itoa(retries+1))(retry)
+		WithHeader(HeaderAttempt, strconv.Itoa(envelope.attempt()+1))(retry)
+		if err := eb.scheduler.schedule(&ScheduledEvent{DueAt: breaker.retryAt(), Envelope: retry}); err != nil {
+			eb.reportError(ctx, StageSchedule, envelope, err)
+			return
+		}
+		eb.recordShortCircuit(ctx, envelope, "retry")
+	case breaker.config.DeadLetter != "":
+		deadLetter := envelope.child(breaker.config.DeadLetter, envelope.Payload)
+		deadLetter.Headers = map[string]string{
+			HeaderDeadLetterEvent:  envelope.Name,
+			HeaderDeadLetterReason: ErrCircuitOpen.Error(),
+		}
+		if err := eb.scheduler.schedule(&ScheduledEvent{DueAt: time.Now(), Envelope: deadLetter}); err != nil {
+			eb.reportError(ctx, StageSchedule, envelope, err)
+			return
+		}
+		eb.recordShortCircuit(ctx, envelope, "dead_letter")
+	default:
+		eb.reportError(ctx, StageDispatch, envelope, ErrCircuitOpen)
+		eb.recordShortCircuit(ctx, envelope, "dropped")
+	}
+}
+
+func (eb *EventBus) recordShortCircuit(ctx context.Context, envelope *EventPayload, outcome string) {
+	eb.breakerRejected.Add(ctx, 1, eb.metricAttrs(
+		attribute.String("event_name", envelope.Name),
+		attribute.String("outcome", outcome),
+	))
+}
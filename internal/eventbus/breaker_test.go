@@ -0,0 +1,284 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestCircuitBreakerTransitions(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerConfig{FailureRatio: 0.5, MinRequests: 4, Window: time.Minute, Cooldown: 10 * time.Second, HalfOpenRequests: 2})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	failure := errors.New("downstream unavailable")
+
+	breaker.record(nil)
+	breaker.record(failure)
+	state, _ := breaker.record(nil)
+	assert.Equal(t, BreakerClosed, state, "O breaker não deve abrir antes do mínimo de requisições")
+	state, changed := breaker.record(failure)
+	assert.Equal(t, BreakerOpen, state)
+	assert.True(t, changed)
+
+	allowed, _ := breaker.allow()
+	assert.False(t, allowed, "Chamadas devem ser bloqueadas com o breaker aberto")
+	assert.Equal(t, now.Add(10*time.Second), breaker.retryAt())
+
+	now = now.Add(10 * time.Second)
+	allowed, state = breaker.allow()
+	assert.True(t, allowed)
+	assert.Equal(t, BreakerHalfOpen, state)
+	allowed, _ = breaker.allow()
+	assert.True(t, allowed)
+	allowed, _ = breaker.allow()
+	assert.False(t, allowed, "Apenas as requisições de teste devem passar no estado half-open")
+
+	breaker.record(nil)
+	state, _ = breaker.record(nil)
+	assert.Equal(t, BreakerClosed, state)
+}
+
+func TestCircuitBreakerReopensOnHalfOpenFailure(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Window: time.Minute, Cooldown: time.Second, HalfOpenRequests: 1})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(errors.New("timeout"))
+	now = now.Add(time.Second)
+	allowed, _ := breaker.allow()
+	assert.True(t, allowed)
+	state, _ := breaker.record(errors.New("timeout"))
+	assert.Equal(t, BreakerOpen, state)
+	assert.Equal(t, now.Add(time.Second), breaker.retryAt(), "O cooldown deve recomeçar ao reabrir")
+}
+
+func TestCircuitBreakerWindowResetsCounts(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerConfig{FailureRatio: 0.5, MinRequests: 2, Window: time.Minute, Cooldown: time.Second, HalfOpenRequests: 1})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+
+	breaker.record(errors.New("timeout"))
+	now = now.Add(2 * time.Minute)
+	state, _ := breaker.record(nil)
+	assert.Equal(t, BreakerClosed, state, "Falhas de janelas anteriores não devem contar")
+}
+
+func TestOpenBreakerRoutesToDeadLetter(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, err := New(
+		WithBatchSize(1),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+		WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 2, Cooldown: time.Hour, DeadLetter: "charge_card_dlq"}),
+	)
+	assert.NoError(t, err)
+
+	var calls int32
+	deadLetters := make(chan *EventPayload, 1)
+	eventBus.Register([]*Event{
+		{
+			Name: "charge_card",
+			Handler: func(payload interface{}) (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				return nil, errors.New("gateway timeout")
+			},
+		},
+	})
+	eventBus.UseHandler(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+			if envelope.Name == "charge_card_dlq" {
+				deadLetters <- envelope
+			}
+			return next(ctx, envelope)
+		}
+	})
+	eventBus.Register([]*Event{{Name: "charge_card_dlq", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	eventBus.ProcessEvent("charge_card", 1)
+	eventBus.ProcessEvent("charge_card", 2)
+	assert.Eventually(t, func() bool {
+		state, _ := eventBus.BreakerState("charge_card")
+		return state == BreakerOpen
+	}, time.Second, 10*time.Millisecond)
+
+	eventBus.ProcessEvent("charge_card", 3)
+	select {
+	case envelope := <-deadLetters:
+		assert.Equal(t, 3, envelope.Payload)
+		assert.Equal(t, "charge_card", envelope.Headers[HeaderDeadLetterEvent])
+		assert.Equal(t, ErrCircuitOpen.Error(), envelope.Headers[HeaderDeadLetterReason])
+	case <-time.After(time.Second):
+		t.Fatal("Eventos bloqueados deveriam ir para a DLQ")
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "O handler não deve ser chamado com o breaker aberto")
+
+	points := collectSums(t, reader, "eventbus.circuit_breaker.rejected")
+	if assert.Len(t, points, 1) {
+		assert.Equal(t, int64(1), points[0].Value)
+		outcome, _ := points[0].Attributes.Value("outcome")
+		assert.Equal(t, "dead_letter", outcome.AsString())
+	}
+}
+
+func TestOpenBreakerSchedulesRetryAfterCooldown(t *testing.T) {
+	eventBus, _ := New(WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute, MaxRetries: 1}))
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }}})
+	breaker := eventBus.breakers["charge_card"]
+	breaker.record(errors.New("gateway timeout"))
+
+	eventBus.ProcessEvent("charge_card", 1)
+	due, _ := eventBus.scheduler.popDue(breaker.retryAt())
+	if assert.Len(t, due, 1) {
+		assert.Equal(t, "1", due[0].Envelope.Headers[HeaderBreakerRetries])
+		assert.Equal(t, "2", due[0].Envelope.Headers[HeaderAttempt], "A nova tentativa deve incrementar o attempt")
+		assert.Equal(t, "charge_card", due[0].Envelope.Name)
+	}
+}
+
+func TestWithCircuitBreakerValidates(t *testing.T) {
+	_, err := New(WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 2}))
+	assert.EqualError(t, err, "failure ratio for event charge_card must be in (0, 1], got 2")
+	_, err = New(WithCircuitBreaker("charge_card", CircuitBreakerConfig{MaxRetries: -1}))
+	assert.EqualError(t, err, "max retries for event charge_card cannot be negative, got -1")
+}
+
+func halfOpenBreaker(t *testing.T, eventBus *EventBus, name string) *circuitBreaker {
+	breaker := eventBus.breakers[name]
+	now := time.Now()
+	breaker.mutex.Lock()
+	breaker.now = func() time.Time { return now }
+	breaker.mutex.Unlock()
+	breaker.record(errors.New("gateway timeout"))
+	breaker.mutex.Lock()
+	now = now.Add(breaker.config.Cooldown)
+	breaker.mutex.Unlock()
+	return breaker
+}
+
+func TestCircuitBreakerReleaseReturnsProbe(t *testing.T) {
+	breaker := newCircuitBreaker(CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Window: time.Minute, Cooldown: time.Second, HalfOpenRequests: 1})
+	now := time.Now()
+	breaker.now = func() time.Time { return now }
+	breaker.record(errors.New("timeout"))
+	now = now.Add(time.Second)
+
+	allowed, state := breaker.allow()
+	assert.True(t, allowed)
+	assert.Equal(t, BreakerHalfOpen, state)
+	allowed, _ = breaker.allow()
+	assert.False(t, allowed)
+	breaker.release()
+	allowed, _ = breaker.allow()
+	assert.True(t, allowed, "Uma sonda liberada sem resultado deve voltar a ficar disponível")
+}
+
+func TestPanickingProbeReopensBreaker(t *testing.T) {
+	eventBus, _ := New(WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute}))
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) { panic("gateway exploded") }}})
+	halfOpenBreaker(t, eventBus, "charge_card")
+
+	eventBus.ProcessEvent("charge_card", 1)
+	assert.Eventually(t, func() bool {
+		state, _ := eventBus.BreakerState("charge_card")
+		return state == BreakerOpen
+	}, time.Second, 10*time.Millisecond, "Um panic na sonda deve reabrir o breaker")
+}
+
+func TestThrottledProbeIsReleased(t *testing.T) {
+	eventBus, _ := New(
+		WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute}),
+		WithHandlerRateLimit("charge_card", RateLimit{Rate: 0.001, Burst: 1, Mode: RateLimitReject}),
+	)
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }}})
+	eventBus.handlerLimits["charge_card"].allow()
+	breaker := halfOpenBreaker(t, eventBus, "charge_card")
+
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	eventBus.ProcessEvent("charge_card", 1)
+	select {
+	case info := <-failures:
+		assert.ErrorIs(t, info.Err, ErrRateLimited)
+	case <-time.After(time.Second):
+		t.Fatal("O handler deveria ser limitado")
+	}
+	allowed, state := breaker.allow()
+	assert.True(t, allowed, "A sonda de um handler limitado deve ser liberada")
+	assert.Equal(t, BreakerHalfOpen, state)
+}
+
+func TestDuplicateDoesNotConsumeProbe(t *testing.T) {
+	store := NewMemoryDedupStore(100, time.Hour)
+	eventBus, _ := New(
+		WithDedupStore(store),
+		WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute}),
+	)
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }}})
+	breaker := halfOpenBreaker(t, eventBus, "charge_card")
+
+	envelope := NewEnvelope("charge_card", 1)
+	_, _ = store.Add(dedupKey(envelope))
+	eventBus.processEnvelope(envelope)
+
+	allowed, _ := breaker.allow()
+	assert.True(t, allowed, "Duplicatas devem ser descartadas antes de consumir a sonda")
+}
+
+func TestDeadLetterDoesNotBlockOnFullRequestQueue(t *testing.T) {
+	eventBus, _ := New(
+		WithRequestQueueSize(1),
+		WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Hour, DeadLetter: "charge_card_dlq"}),
+	)
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }}})
+	eventBus.breakers["charge_card"].record(errors.New("gateway timeout"))
+	for _, lane := range eventBus.requestLanes {
+		for len(lane) < cap(lane) {
+			lane <- NewEnvelope("filler", nil)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		eventBus.ProcessEvent("charge_card", 1)
+		eventBus.ProcessEvent("charge_card", 2)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Enviar para a DLQ não deve bloquear o loop de despacho")
+	}
+	due, _ := eventBus.scheduler.popDue(time.Now())
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, "charge_card_dlq", due[0].Envelope.Name)
+	}
+}
+
+func TestBreakerRecordsOneOutcomePerEnvelope(t *testing.T) {
+	eventBus, _ := New(WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute, HalfOpenRequests: 2}))
+	handler := func(payload interface{}) (interface{}, error) { return nil, nil }
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: handler}, {Name: "charge_card", Handler: handler}})
+	breaker := halfOpenBreaker(t, eventBus, "charge_card")
+	successes := func() int {
+		breaker.mutex.Lock()
+		defer breaker.mutex.Unlock()
+		return breaker.successes
+	}
+
+	eventBus.ProcessEvent("charge_card", 1)
+	assert.Eventually(t, func() bool { return successes() == 1 }, time.Second, 10*time.Millisecond)
+	state, _ := eventBus.BreakerState("charge_card")
+	assert.Equal(t, BreakerHalfOpen, state, "Vários handlers do mesmo evento devem contar como uma única sonda")
+
+	eventBus.ProcessEvent("charge_card", 2)
+	assert.Eventually(t, func() bool {
+		state, _ := eventBus.BreakerState("charge_card")
+		return state == BreakerClosed
+	}, time.Second, 10*time.Millisecond)
+}
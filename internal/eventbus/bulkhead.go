@@ -0,0 +1,130 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+type bulkhead struct {
+	name  string
+	kind  string
+	slots chan struct{}
+}
+
+func newBulkhead(name, kind string, size int) *bulkhead {
+	return &bulkhead{name: name, kind: kind, slots: make(chan struct{}, size)}
+}
+
+func newBulkheads(config EventBusConfig) map[string][]*bulkhead {
+	pools := make(map[string]*bulkhead, len(config.Bulkheads))
+	for name, size := range config.Bulkheads {
+		pools[name] = newBulkhead(name, "pool", size)
+	}
+	events := make(map[string][]*bulkhead)
+	for name, limit := range config.EventConcurrency {
+		events[name] = append(events[name], newBulkhead(name, "event", limit))
+	}
+	for name, pool := range config.EventBulkheads {
+		events[name] = append(events[name], pools[pool])
+	}
+	return events
+}
+
+func validateBulkheads(config EventBusConfig) error {
+	for name, size := range config.Bulkheads {
+		if size < 1 {
+			return fmt.Errorf("bulkhead %s size must be positive, got %d", name, size)
+		}
+	}
+	for name, limit := range config.EventConcurrency {
+		if limit < 1 {
+			return fmt.Errorf("concurrency limit for event %s must be positive, got %d", name, limit)
+		}
+	}
+	for name, pool := range config.EventBulkheads {
+		if _, ok := config.Bulkheads[pool]; !ok {
+			return fmt.Errorf("unknown bulkhead %s for event %s", pool, name)
+		}
+	}
+	return nil
+}
+
+func (eb *EventBus) acquireBulkheads(ctx context.Context, e *Event) func() {
+	bulkheads := eb.eventBulkheads[e.Name]
+	for _, b := range bulkheads {
+		attrs := eb.metricAttrs(attribute.String("bulkhead", b.name), attribute.String("kind", b.kind))
+		start := time.Now()
+		eb.bulkheadQueued.Add(ctx, 1, attrs)
+		b.slots <- struct{}{}
+		eb.bulkheadQueued.Add(ctx, -1, attrs)
+		eb.bulkheadWait.Record(ctx, time.Since(start).Seconds(), attrs)
+		eb.bulkheadActive.Add(ctx, 1, attrs)
+	}
+	return func() {
+		for i := len(bulkheads) - 1; i >= 0; i-- {
+			b := bulkheads[i]
+			<-b.slots
+			eb.bulkheadActive.Add(ctx, -1, eb.metricAttrs(attribute.String("bulkhead", b.name), attribute.String("kind", b.kind)))
+		}
+	}
+}
+
+func (eb *EventBus) acquireWorker(ctx context.Context, envelope *EventPayload, e *Event) (func(), bool) {
+	defer func() { <-eb.pendingHandlers }()
+	throttleCtx, cancel := context.WithTimeout(ctx, eb.config.Timeout)
+	err := eb.throttle(throttleCtx, eb.handlerLimits, "handler", envelope)
+	cancel()
+	if err != nil {
+		eb.reportError(ctx, StageDispatch, envelope, err)
+		eb.releaseBreaker(ctx, envelope)
+		eb.forget(ctx, envelope)
+		return nil, false
+	}
+	release := eb.acquireBulkheads(ctx, e)
+	eb.workerPool <- struct{}{}
+	return func() {
+		<-eb.workerPool
+		release()
+	}, true
+}
+
+func (eb *EventBus) runWorker(ctx context.Context, envelope *EventPayload, e *Event) {
+	release, ok := eb.acquireWorker(ctx, envelope, e)
+	if !ok {
+		return
+	}
+	defer release()
+	eb.invokeHandler(ctx, envelope, e)
+}
+
+func (eb *EventBus) dispatch(ctx context.Context, envelope *EventPayload, e *Event) {
+	if _, limited := eb.handlerLimits[e.Name]; !limited && len(eb.eventBulkheads[e.Name]) == 0 {
+		eb.workerPool <- struct{}{}
+		go func() {
+			defer func() { <-eb.workerPool }()
+			eb.invokeHandler(ctx, envelope, e)
+		}()
+		return
+	}
+
+	eb.pendingHandlers <- struct{}{}
+	go eb.runWorker(ctx, envelope, e)
+}
+
+func (eb *EventBus) newBulkheadMetrics(meter metric.Meter) error {
+	var err error
+	eb.bulkheadQueued, err = meter.Int64UpDownCounter("eventbus.bulkhead.queued", metric.WithDescription("Number of handler invocations waiting for a bulkhead slot"))
+	if err != nil {
+		return err
+	}
+	eb.bulkheadActive, err = meter.Int64UpDownCounter("eventbus.bulkhead.active", metric.WithDescription("Number of handler invocations holding a bulkhead slot"))
+	if err != nil {
+		return err
+	}
+	eb.bulkheadWait, err = meter.Float64Histogram("eventbus.bulkhead.wait", metric.WithDescription("Time spent waiting for a bulkhead slot"), metric.WithUnit("s"))
+	return err
+}
@@ -0,0 +1,186 @@
+package eventbus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type concurrencyProbe struct {
+	active  int32
+	peak    int32
+	release chan struct{}
+	started chan struct{}
+}
+
+func newConcurrencyProbe() *concurrencyProbe {
+	return &concurrencyProbe{release: make(chan struct{}), started: make(chan struct{}, 100)}
+}
+
+func (p *concurrencyProbe) handler(payload interface{}) (interface{}, error) {
+	active := atomic.AddInt32(&p.active, 1)
+	for {
+		peak := atomic.LoadInt32(&p.peak)
+		if active <= peak || atomic.CompareAndSwapInt32(&p.peak, peak, active) {
+			break
+		}
+	}
+	p.started <- struct{}{}
+	<-p.release
+	atomic.AddInt32(&p.active, -1)
+	return nil, nil
+}
+
+func TestEventConcurrencyLimit(t *testing.T) {
+	eventBus, _ := New(WithEventConcurrency("call_api", 2), WithTimeout(time.Minute))
+	probe := newConcurrencyProbe()
+	eventBus.Register([]*Event{{Name: "call_api", Handler: probe.handler}})
+
+	for i := 0; i < 5; i++ {
+		eventBus.ProcessEvent("call_api", i)
+	}
+	<-probe.started
+	<-probe.started
+	select {
+	case <-probe.started:
+		t.Fatal("O limite de concorrência do evento deve ser respeitado")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(probe.release)
+	for i := 0; i < 3; i++ {
+		<-probe.started
+	}
+	assert.Equal(t, int32(2), atomic.LoadInt32(&probe.peak))
+}
+
+func TestSlowBulkheadDoesNotStarveOtherHandlers(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, err := New(
+		WithWorkerPoolSize(4),
+		WithTimeout(time.Minute),
+		WithBulkhead("reports", 1),
+		WithEventBulkhead("generate_report", "reports"),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	assert.NoError(t, err)
+	probe := newConcurrencyProbe()
+	fast := make(chan struct{}, 1)
+	eventBus.Register([]*Event{
+		{Name: "generate_report", Handler: probe.handler},
+		{Name: "send_email", Handler: func(payload interface{}) (interface{}, error) {
+			fast <- struct{}{}
+			return nil, nil
+		}},
+	})
+
+	for i := 0; i < 6; i++ {
+		eventBus.ProcessEvent("generate_report", i)
+	}
+	<-probe.started
+	eventBus.ProcessEvent("send_email", nil)
+	select {
+	case <-fast:
+	case <-time.After(time.Second):
+		t.Fatal("Handlers fora do bulkhead não devem ser bloqueados")
+	}
+
+	assert.Eventually(t, func() bool {
+		var resourceMetrics metricdata.ResourceMetrics
+		assert.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+		for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+			for _, m := range scopeMetrics.Metrics {
+				if m.Name != "eventbus.bulkhead.queued" {
+					continue
+				}
+				for _, point := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					if bulkhead, _ := point.Attributes.Value("bulkhead"); bulkhead.AsString() == "reports" {
+						return point.Value == 5
+					}
+				}
+			}
+		}
+		return false
+	}, time.Second, 10*time.Millisecond, "A fila do bulkhead deve ser medida por pool")
+
+	close(probe.release)
+	for i := 0; i < 5; i++ {
+		<-probe.started
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&probe.peak))
+}
+
+func TestKeyedAndUnkeyedEventsShareBulkheadWithoutDeadlock(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{
+		WorkerPoolSize:   1,
+		EventConcurrency: map[string]int{"sync_stock": 1},
+		Timeout:          time.Minute,
+	})
+	probe := newConcurrencyProbe()
+	eventBus.Register([]*Event{{Name: "sync_stock", Handler: probe.handler}})
+
+	for i := 0; i < 4; i++ {
+		eventBus.processEnvelope(NewEnvelope("sync_stock", i))
+		eventBus.processEnvelope(NewEnvelope("sync_stock", i, WithOrderingKey("sku-1")))
+	}
+	close(probe.release)
+	for i := 0; i < 8; i++ {
+		select {
+		case <-probe.started:
+		case <-time.After(time.Second):
+			t.Fatal("Eventos com e sem chave no mesmo bulkhead não devem travar o worker pool")
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&probe.peak))
+}
+
+func TestFullBulkheadQueueBlocksDispatcher(t *testing.T) {
+	eventBus, _ := New(
+		WithWorkerPoolSize(1),
+		WithPendingQueueSize(2),
+		WithTimeout(time.Minute),
+		WithBulkhead("reports", 1),
+		WithEventBulkhead("generate_report", "reports"),
+	)
+	probe := newConcurrencyProbe()
+	eventBus.Register([]*Event{{Name: "generate_report", Handler: probe.handler}})
+
+	eventBus.ProcessEvent("generate_report", 0)
+	<-probe.started
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i < 4; i++ {
+			eventBus.ProcessEvent("generate_report", i)
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("O despacho deve bloquear quando a fila de handlers pendentes estiver cheia")
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Equal(t, 2, len(eventBus.pendingHandlers))
+
+	close(probe.release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("O despacho deve continuar quando o bulkhead liberar espaço")
+	}
+	for i := 1; i < 4; i++ {
+		<-probe.started
+	}
+}
+
+func TestBulkheadValidation(t *testing.T) {
+	_, err := New(WithEventBulkhead("generate_report", "reports"))
+	assert.EqualError(t, err, "unknown bulkhead reports for event generate_report")
+	_, err = New(WithBulkhead("reports", 0))
+	assert.EqualError(t, err, "bulkhead reports size must be positive, got 0")
+	_, err = New(WithEventConcurrency("call_api", -1))
+	assert.EqualError(t, err, "concurrency limit for event call_api must be positive, got -1")
+}
@@ -0,0 +1,26 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONCodecRoundTrip(t *testing.T) {
+	codec := JSONCodec{}
+
+	data, err := codec.Marshal(map[string]interface{}{"order_id": "42", "amount": 10.5})
+	assert.NoError(t, err)
+
+	var decoded map[string]interface{}
+	err = codec.Unmarshal(data, &decoded)
+	assert.NoError(t, err)
+	assert.Equal(t, "42", decoded["order_id"])
+	assert.Equal(t, 10.5, decoded["amount"])
+}
+
+func TestJSONCodecUnmarshalError(t *testing.T) {
+	var decoded interface{}
+	err := JSONCodec{}.Unmarshal([]byte("{"), &decoded)
+	assert.Error(t, err)
+}
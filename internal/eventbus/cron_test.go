@@ -0,0 +1,59 @@
+package eventbus
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronNext(t *testing.T) {
+	base := time.Date(2024, time.January, 15, 10, 7, 30, 0, time.UTC)
+	tests := []struct {
+		expr string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2024, time.January, 15, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2024, time.January, 15, 10, 15, 0, 0, time.UTC)},
+		{"0 9-17 * * *", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"30 8 * * 1,3", time.Date(2024, time.January, 17, 8, 30, 0, 0, time.UTC)},
+		{"0 0 1 * *", time.Date(2024, time.February, 1, 0, 0, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2024, time.January, 15, 10, 25, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2024, time.January, 21, 0, 0, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2024, time.January, 15, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2024, time.January, 16, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", base.Add(90 * time.Second)},
+	}
+	for _, test := range tests {
+		schedule, err := ParseCron(test.expr)
+		if assert.NoError(t, err, test.expr) {
+			assert.Equal(t, test.next, schedule.Next(base), test.expr)
+		}
+	}
+}
+
+func TestParseCronDayOfMonthOrDayOfWeek(t *testing.T) {
+	schedule, err := ParseCron("0 0 20 * 1")
+	assert.NoError(t, err)
+
+	base := time.Date(2024, time.January, 15, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC), schedule.Next(base),
+		"Com dia do mês e dia da semana restritos, qualquer um dos dois deve disparar")
+	assert.Equal(t, time.Date(2024, time.January, 22, 0, 0, 0, 0, time.UTC), schedule.Next(time.Date(2024, time.January, 20, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestParseCronErrors(t *testing.T) {
+	tests := map[string]string{
+		"* * * *":      `invalid cron expression "* * * *": expected 5 fields, got 4`,
+		"60 * * * *":   `invalid cron expression "60 * * * *": minute: range 60-60 outside 0-59`,
+		"* * * 0 *":    `invalid cron expression "* * * 0 *": month: range 0-0 outside 1-12`,
+		"*/0 * * * *":  `invalid cron expression "*/0 * * * *": minute: invalid step "0"`,
+		"a * * * *":    `invalid cron expression "a * * * *": minute: invalid value "a"`,
+		"@every -1s":   `invalid cron expression "@every -1s": interval must be positive`,
+		"@every never": `invalid cron expression "@every never": time: invalid duration "never"`,
+	}
+	for expr, expected := range tests {
+		_, err := ParseCron(expr)
+		assert.EqualError(t, err, expected)
+	}
+}
@@ -0,0 +1,245 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/jsonfile"
+)
+
+const maxCatchUpRuns = 1000
+
+type MissedRunPolicy int
+
+const (
+	MissedRunSkip MissedRunPolicy = iota
+	MissedRunCatchUp
+)
+
+type CronJob struct {
+	Name       string
+	Schedule   string
+	EventName  string
+	Payload    func(scheduledAt time.Time) (interface{}, error)
+	MissedRuns MissedRunPolicy
+}
+
+type CronStateStore interface {
+	LastRun(job string) (time.Time, bool, error)
+	SetLastRun(job string, at time.Time) error
+}
+
+type MemoryCronStateStore struct {
+	mutex    *sync.Mutex
+	lastRuns map[string]time.Time
+}
+
+func NewMemoryCronStateStore() *MemoryCronStateStore {
+	return &MemoryCronStateStore{
+		mutex:    &sync.Mutex{},
+		lastRuns: make(map[string]time.Time),
+	}
+}
+
+func (s *MemoryCronStateStore) LastRun(job string) (time.Time, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lastRun, ok := s.lastRuns[job]
+	return lastRun, ok, nil
+}
+
+func (s *MemoryCronStateStore) SetLastRun(job string, at time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.lastRuns[job] = at
+	return nil
+}
+
+type FileCronStateStore struct {
+	mutex *sync.Mutex
+	path  string
+}
+
+func NewFileCronStateStore(path string) (*FileCronStateStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cron state directory: %w", err)
+	}
+	return &FileCronStateStore{
+		mutex: &sync.Mutex{},
+		path:  path,
+	}, nil
+}
+
+func (s *FileCronStateStore) read() (map[string]time.Time, error) {
+	lastRuns := make(map[string]time.Time)
+	if err := jsonfile.Load(s.path, "cron state", &lastRuns); err != nil {
+		return nil, err
+	}
+	return lastRuns, nil
+}
+
+func (s *FileCronStateStore) LastRun(job string) (time.Time, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lastRuns, err := s.read()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	lastRun, ok := lastRuns[job]
+	return lastRun, ok, nil
+}
+
+func (s *FileCronStateStore) SetLastRun(job string, at time.Time) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	lastRuns, err := s.read()
+	if err != nil {
+		return err
+	}
+	lastRuns[job] = at
+	return jsonfile.Save(s.path, "cron state", lastRuns)
+}
+
+type cronEntry struct {
+	job      CronJob
+	schedule CronSchedule
+	next     time.Time
+	backlog  []time.Time
+}
+
+type cronRunner struct {
+	mutex   *sync.Mutex
+	entries map[string]*cronEntry
+	store   CronStateStore
+	wakeup  chan struct{}
+	done    chan struct{}
+}
+
+func newCronRunner(store CronStateStore) *cronRunner {
+	if store == nil {
+		store = NewMemoryCronStateStore()
+	}
+	return &cronRunner{
+		mutex:   &sync.Mutex{},
+		entries: make(map[string]*cronEntry),
+		store:   store,
+		wakeup:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+}
+
+func (eb *EventBus) AddCronJob(job CronJob) error {
+	if job.Name == "" {
+		return errors.New("cron job name cannot be empty")
+	}
+	if job.EventName == "" {
+		return fmt.Errorf("cron job %s has no event name", job.Name)
+	}
+	schedule, err := ParseCron(job.Schedule)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	entry := &cronEntry{job: job, schedule: schedule, next: schedule.Next(now)}
+	lastRun, ok, err := eb.cron.store.LastRun(job.Name)
+	if err != nil {
+		return err
+	}
+	if ok && job.MissedRuns == MissedRunCatchUp {
+		for missed := schedule.Next(lastRun); !missed.IsZero() && !missed.After(now); missed = schedule.Next(missed) {
+			if len(entry.backlog) == maxCatchUpRuns {
+				break
+			}
+			entry.backlog = append(entry.backlog, missed)
+		}
+	}
+
+	eb.cron.mutex.Lock()
+	defer eb.cron.mutex.Unlock()
+	if _, exists := eb.cron.entries[job.Name]; exists {
+		return fmt.Errorf("cron job %s already registered", job.Name)
+	}
+	eb.cron.entries[job.Name] = entry
+	select {
+	case eb.cron.wakeup <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (eb *EventBus) runCron() {
+	defer close(eb.cron.done)
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		now := time.Now()
+		next := time.Time{}
+		var runs []func()
+
+		eb.cron.mutex.Lock()
+		for _, entry := range eb.cron.entries {
+			entry := entry
+			for _, missed := range entry.backlog {
+				missed := missed
+				runs = append(runs, func() { eb.fireCronJob(entry.job, missed) })
+			}
+			entry.backlog = nil
+			for !entry.next.IsZero() && !entry.next.After(now) {
+				scheduledAt := entry.next
+				runs = append(runs, func() { eb.fireCronJob(entry.job, scheduledAt) })
+				entry.next = entry.schedule.Next(scheduledAt)
+				if entry.job.MissedRuns == MissedRunSkip && !entry.next.After(now) {
+					entry.next = entry.schedule.Next(now)
+				}
+			}
+			if !entry.next.IsZero() && (next.IsZero() || entry.next.Before(next)) {
+				next = entry.next
+			}
+		}
+		eb.cron.mutex.Unlock()
+
+		for _, run := range runs {
+			run()
+		}
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-eb.stopChannel:
+			return
+		case <-timer.C:
+		case <-eb.cron.wakeup:
+		}
+	}
+}
+
+func (eb *EventBus) fireCronJob(job CronJob, scheduledAt time.Time) {
+	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
+	defer cancel()
+	envelope := NewEnvelope(job.EventName, nil, WithHeader("cron_job", job.Name), WithHeader("cron_scheduled_at", scheduledAt.UTC().Format(time.RFC3339)))
+
+	if job.Payload != nil {
+		payload, err := job.Payload(scheduledAt)
+		if err != nil {
+			eb.reportError(ctx, StageSchedule, envelope, fmt.Errorf("cron job %s payload: %w", job.Name, err))
+			return
+		}
+		envelope.Payload = payload
+	}
+	if err := eb.publishEnvelope(ctx, envelope); err != nil {
+		eb.reportError(ctx, StageSchedule, envelope, fmt.Errorf("cron job %s: %w", job.Name, err))
+		return
+	}
+	if err := eb.cron.store.SetLastRun(job.Name, scheduledAt); err != nil {
+		eb.reportError(ctx, StageSchedule, envelope, fmt.Errorf("cron job %s: %w", job.Name, err))
+	}
+}
@@ -0,0 +1,148 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddCronJobValidation(t *testing.T) {
+	eventBus, _ := New()
+
+	assert.EqualError(t, eventBus.AddCronJob(CronJob{Schedule: "@hourly", EventName: "report"}), "cron job name cannot be empty")
+	assert.EqualError(t, eventBus.AddCronJob(CronJob{Name: "report", Schedule: "@hourly"}), "cron job report has no event name")
+	assert.Error(t, eventBus.AddCronJob(CronJob{Name: "report", Schedule: "bogus", EventName: "report"}))
+
+	assert.NoError(t, eventBus.AddCronJob(CronJob{Name: "report", Schedule: "@hourly", EventName: "report"}))
+	assert.EqualError(t, eventBus.AddCronJob(CronJob{Name: "report", Schedule: "@daily", EventName: "report"}), "cron job report already registered")
+}
+
+func TestCronJobEmitsEvents(t *testing.T) {
+	store := NewMemoryCronStateStore()
+	eventBus, _ := New(WithBatchSize(1), WithCronStateStore(store))
+	received := make(chan *EventPayload, 10)
+	eventBus.Register([]*Event{{
+		Name: "cleanup",
+		Handler: func(payload interface{}) (interface{}, error) {
+			received <- &EventPayload{Payload: payload}
+			return nil, nil
+		},
+	}})
+	assert.NoError(t, eventBus.AddCronJob(CronJob{
+		Name:      "cleanup-job",
+		Schedule:  "@every 20ms",
+		EventName: "cleanup",
+		Payload: func(scheduledAt time.Time) (interface{}, error) {
+			return scheduledAt.Format(time.RFC3339Nano), nil
+		},
+	}))
+	eventBus.Start()
+
+	for i := 0; i < 2; i++ {
+		select {
+		case eventPayload := <-received:
+			assert.NotEmpty(t, eventPayload.Payload)
+		case <-time.After(time.Second):
+			t.Fatal("O cron job deveria ter emitido eventos")
+		}
+	}
+	eventBus.Stop()
+
+	lastRun, ok, err := store.LastRun("cleanup-job")
+	assert.NoError(t, err)
+	assert.True(t, ok, "A última execução deve ser registrada")
+	assert.False(t, lastRun.IsZero())
+}
+
+func TestCronJobCatchUpMissedRuns(t *testing.T) {
+	store := NewMemoryCronStateStore()
+	assert.NoError(t, store.SetLastRun("digest", time.Now().Add(-3*time.Hour-time.Minute)))
+
+	eventBus, _ := New(WithBatchSize(1), WithCronStateStore(store))
+	var mutex sync.Mutex
+	var scheduled []time.Time
+	done := make(chan struct{}, 10)
+	eventBus.Register([]*Event{{
+		Name:    "send_digest",
+		Handler: func(payload interface{}) (interface{}, error) { done <- struct{}{}; return nil, nil },
+	}})
+	assert.NoError(t, eventBus.AddCronJob(CronJob{
+		Name:       "digest",
+		Schedule:   "@every 1h",
+		EventName:  "send_digest",
+		MissedRuns: MissedRunCatchUp,
+		Payload: func(scheduledAt time.Time) (interface{}, error) {
+			mutex.Lock()
+			defer mutex.Unlock()
+			scheduled = append(scheduled, scheduledAt)
+			return nil, nil
+		},
+	}))
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	for i := 0; i < 3; i++ {
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("As execuções perdidas deveriam ter sido recuperadas")
+		}
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Len(t, scheduled, 3)
+	assert.True(t, scheduled[0].Before(scheduled[1]) && scheduled[1].Before(scheduled[2]), "As execuções devem ser emitidas em ordem")
+}
+
+func TestCronJobSkipMissedRuns(t *testing.T) {
+	store := NewMemoryCronStateStore()
+	assert.NoError(t, store.SetLastRun("digest", time.Now().Add(-3*time.Hour-time.Minute)))
+
+	eventBus, _ := New(WithCronStateStore(store))
+	assert.NoError(t, eventBus.AddCronJob(CronJob{Name: "digest", Schedule: "@every 1h", EventName: "send_digest"}))
+
+	entry := eventBus.cron.entries["digest"]
+	assert.Empty(t, entry.backlog, "Execuções perdidas devem ser ignoradas com MissedRunSkip")
+	assert.True(t, entry.next.After(time.Now()))
+}
+
+func TestCronJobPayloadError(t *testing.T) {
+	eventBus, _ := New()
+	var info ErrorInfo
+	eventBus.OnError(func(ctx context.Context, i ErrorInfo) { info = i })
+
+	eventBus.fireCronJob(CronJob{
+		Name:      "report",
+		EventName: "report",
+		Payload:   func(time.Time) (interface{}, error) { return nil, errors.New("db unavailable") },
+	}, time.Now())
+
+	assert.Equal(t, StageSchedule, info.Stage)
+	assert.EqualError(t, info.Err, "cron job report payload: db unavailable")
+	assert.Len(t, eventBus.requestQueue, 0)
+}
+
+func TestFileCronStateStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cron", "state.json")
+	store, err := NewFileCronStateStore(path)
+	assert.NoError(t, err)
+
+	_, ok, err := store.LastRun("report")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	at := time.Date(2024, time.January, 15, 10, 0, 0, 0, time.UTC)
+	assert.NoError(t, store.SetLastRun("report", at))
+
+	reopened, err := NewFileCronStateStore(path)
+	assert.NoError(t, err)
+	lastRun, ok, err := reopened.LastRun("report")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, at.Equal(lastRun))
+}
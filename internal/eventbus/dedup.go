@@ -0,0 +1,212 @@
+package eventbus
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const HeaderIdempotencyKey = "idempotency_key"
+
+type DedupStore interface {
+	Add(key string) (bool, error)
+	Remove(key string) error
+}
+
+func WithIdempotencyKey(key string) PublishOption {
+	return WithHeader(HeaderIdempotencyKey, key)
+}
+
+func dedupKey(envelope *EventPayload) string {
+	if key := envelope.Headers[HeaderIdempotencyKey]; key != "" {
+		return envelope.Name + "/" + key
+	}
+	return envelope.ID
+}
+
+type MemoryDedupStore struct {
+	mutex    *sync.Mutex
+	capacity int
+	ttl      time.Duration
+	order    *list.List
+	entries  map[string]*list.Element
+	now      func() time.Time
+}
+
+type dedupEntry struct {
+	key    string
+	seenAt time.Time
+}
+
+func NewMemoryDedupStore(capacity int, ttl time.Duration) *MemoryDedupStore {
+	return &MemoryDedupStore{
+		mutex:    &sync.Mutex{},
+		capacity: capacity,
+		ttl:      ttl,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+		now:      time.Now,
+	}
+}
+
+func (s *MemoryDedupStore) Add(key string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	now := s.now()
+	if element, ok := s.entries[key]; ok {
+		entry := element.Value.(*dedupEntry)
+		if s.ttl <= 0 || now.Sub(entry.seenAt) < s.ttl {
+			s.order.MoveToFront(element)
+			return false, nil
+		}
+		entry.seenAt = now
+		s.order.MoveToFront(element)
+		return true, nil
+	}
+
+	s.entries[key] = s.order.PushFront(&dedupEntry{key: key, seenAt: now})
+	for s.capacity > 0 && s.order.Len() > s.capacity {
+		oldest := s.order.Back()
+		s.order.Remove(oldest)
+		delete(s.entries, oldest.Value.(*dedupEntry).key)
+	}
+	return true, nil
+}
+
+func (s *MemoryDedupStore) Remove(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.order.Remove(element)
+		delete(s.entries, key)
+	}
+	return nil
+}
+
+func (s *MemoryDedupStore) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.order.Len()
+}
+
+type FileDedupStore struct {
+	mutex *sync.Mutex
+	dir   string
+	ttl   time.Duration
+}
+
+func NewFileDedupStore(dir string, ttl time.Duration) (*FileDedupStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create dedup dir: %w", err)
+	}
+	return &FileDedupStore{mutex: &sync.Mutex{}, dir: dir, ttl: ttl}, nil
+}
+
+func (s *FileDedupStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".seen")
+}
+
+func (s *FileDedupStore) Add(key string) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	path := s.path(key)
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+	if err == nil {
+		return true, file.Close()
+	}
+	if !errors.Is(err, os.ErrExist) {
+		return false, fmt.Errorf("failed to record event %s: %w", key, err)
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return false, fmt.Errorf("failed to read event %s: %w", key, err)
+	}
+	if s.ttl <= 0 || time.Since(info.ModTime()) < s.ttl {
+		return false, nil
+	}
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		return false, fmt.Errorf("failed to record event %s: %w", key, err)
+	}
+	return true, nil
+}
+
+func (s *FileDedupStore) Remove(key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.Remove(s.path(key)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove event %s: %w", key, err)
+	}
+	return nil
+}
+
+func (s *FileDedupStore) Prune() error {
+	if s.ttl <= 0 {
+		return nil
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read dedup dir: %w", err)
+	}
+	for _, entry := range entries {
+		if filepath.Ext(entry.Name()) != ".seen" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			continue
+		}
+		if time.Since(info.ModTime()) >= s.ttl {
+			if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("failed to prune dedup dir: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+func (eb *EventBus) isDuplicate(ctx context.Context, envelope *EventPayload) bool {
+	if eb.config.DedupStore == nil {
+		return false
+	}
+	key := dedupKey(envelope)
+	if key == "" {
+		return false
+	}
+	added, err := eb.config.DedupStore.Add(key)
+	if err != nil {
+		eb.reportError(ctx, StageDispatch, envelope, fmt.Errorf("failed to check dedup store: %w", err))
+		return false
+	}
+	if !added {
+		eb.duplicateCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("event_name", envelope.Name)))
+		eb.logger.DebugContext(ctx, "duplicate event skipped", eb.logAttrs(ctx, envelope)...)
+	}
+	return !added
+}
+
+func (eb *EventBus) forget(ctx context.Context, envelope *EventPayload) {
+	key := dedupKey(envelope)
+	if eb.config.DedupStore == nil || key == "" || envelope.replayed() {
+		return
+	}
+	if err := eb.config.DedupStore.Remove(key); err != nil {
+		eb.reportError(ctx, StageDispatch, envelope, fmt.Errorf("failed to release dedup key: %w", err))
+	}
+}
@@ -0,0 +1,205 @@
+package eventbus
+
+import (
+	"errors"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestMemoryDedupStoreEvictsLeastRecentlyUsed(t *testing.T) {
+	store := NewMemoryDedupStore(2, 0)
+
+	for _, key := range []string{"a", "b"} {
+		added, err := store.Add(key)
+		assert.NoError(t, err)
+		assert.True(t, added)
+	}
+	added, _ := store.Add("a")
+	assert.False(t, added, "Chaves repetidas devem ser identificadas como duplicadas")
+
+	added, _ = store.Add("c")
+	assert.True(t, added)
+	assert.Equal(t, 2, store.Len())
+
+	added, _ = store.Add("b")
+	assert.True(t, added, "A chave menos usada recentemente deve ser descartada")
+}
+
+func TestMemoryDedupStoreExpiresEntries(t *testing.T) {
+	store := NewMemoryDedupStore(10, time.Minute)
+	now := time.Now()
+	store.now = func() time.Time { return now }
+
+	added, _ := store.Add("a")
+	assert.True(t, added)
+	now = now.Add(30 * time.Second)
+	added, _ = store.Add("a")
+	assert.False(t, added)
+	now = now.Add(2 * time.Minute)
+	added, _ = store.Add("a")
+	assert.True(t, added, "Chaves expiradas devem ser aceitas novamente")
+
+	assert.NoError(t, store.Remove("a"))
+	added, _ = store.Add("a")
+	assert.True(t, added)
+}
+
+func TestFileDedupStorePersistsAcrossInstances(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileDedupStore(dir, time.Hour)
+	assert.NoError(t, err)
+
+	added, err := store.Add("order-1")
+	assert.NoError(t, err)
+	assert.True(t, added)
+
+	reopened, err := NewFileDedupStore(dir, time.Hour)
+	assert.NoError(t, err)
+	added, err = reopened.Add("order-1")
+	assert.NoError(t, err)
+	assert.False(t, added, "Chaves registradas devem sobreviver à reabertura do store")
+
+	assert.NoError(t, reopened.Remove("order-1"))
+	added, _ = reopened.Add("order-1")
+	assert.True(t, added)
+}
+
+func TestFileDedupStorePrunesExpiredEntries(t *testing.T) {
+	store, err := NewFileDedupStore(t.TempDir(), time.Hour)
+	assert.NoError(t, err)
+	_, _ = store.Add("order-1")
+
+	expired := time.Now().Add(-2 * time.Hour)
+	assert.NoError(t, os.Chtimes(store.path("order-1"), expired, expired))
+	assert.NoError(t, store.Prune())
+
+	_, err = os.Stat(store.path("order-1"))
+	assert.True(t, os.IsNotExist(err), "Entradas expiradas devem ser removidas")
+}
+
+func TestRedeliveredEventIsProcessedOnce(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, _ := New(
+		WithDedupStore(NewMemoryDedupStore(100, time.Hour)),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	var calls int32
+	processed := make(chan struct{}, 2)
+	eventBus.Register([]*Event{{
+		Name: "charge_card",
+		Handler: func(payload interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			processed <- struct{}{}
+			return nil, nil
+		},
+	}})
+
+	envelope := NewEnvelope("charge_card", 100)
+	eventBus.processEnvelope(envelope)
+	eventBus.processEnvelope(envelope)
+	<-processed
+
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls), "Eventos reentregues não devem ser processados novamente")
+	points := collectSums(t, reader, "eventbus.duplicates")
+	if assert.Len(t, points, 1) {
+		assert.Equal(t, int64(1), points[0].Value)
+	}
+}
+
+func TestEnvelopeWithoutIDSkipsDedup(t *testing.T) {
+	store := NewMemoryDedupStore(100, time.Hour)
+	eventBus, _ := New(WithDedupStore(store))
+	var calls int32
+	eventBus.Register([]*Event{{
+		Name: "charge_card",
+		Handler: func(payload interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, nil
+		},
+	}})
+
+	eventBus.processEnvelope(&EventPayload{Name: "charge_card", Payload: 100})
+	eventBus.processEnvelope(&EventPayload{Name: "charge_card", Payload: 200})
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, 10*time.Millisecond, "Eventos sem ID não devem ser tratados como duplicados")
+	assert.Equal(t, 0, store.Len(), "Eventos sem ID não devem ocupar o dedup store")
+}
+
+func TestIdempotencyKeyDeduplicatesDistinctEnvelopes(t *testing.T) {
+	eventBus, _ := New(WithDedupStore(NewMemoryDedupStore(100, time.Hour)))
+	var calls int32
+	eventBus.Register([]*Event{{
+		Name: "charge_card",
+		Handler: func(payload interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, nil
+		},
+	}})
+
+	eventBus.processEnvelope(NewEnvelope("charge_card", 100, WithIdempotencyKey("payment-1")))
+	eventBus.processEnvelope(NewEnvelope("charge_card", 100, WithIdempotencyKey("payment-1")))
+	eventBus.processEnvelope(NewEnvelope("charge_card", 100, WithIdempotencyKey("payment-2")))
+
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 2 }, time.Second, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestFailedEventCanBeRedelivered(t *testing.T) {
+	store := NewMemoryDedupStore(100, time.Hour)
+	eventBus, _ := New(WithDedupStore(store))
+	var calls int32
+	done := make(chan struct{}, 2)
+	eventBus.Register([]*Event{{
+		Name: "charge_card",
+		Handler: func(payload interface{}) (interface{}, error) {
+			defer func() { done <- struct{}{} }()
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return nil, errors.New("gateway timeout")
+			}
+			return nil, nil
+		},
+	}})
+
+	envelope := NewEnvelope("charge_card", 100)
+	eventBus.processEnvelope(envelope)
+	<-done
+	assert.Eventually(t, func() bool { return store.Len() == 0 }, time.Second, 10*time.Millisecond)
+	eventBus.processEnvelope(envelope)
+	<-done
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "Eventos com falha devem poder ser reprocessados")
+}
+
+func TestDedupKeyIsRecordedBeforeHandlerRuns(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileDedupStore(dir, time.Hour)
+	assert.NoError(t, err)
+	eventBus, _ := New(WithDedupStore(store), WithTimeout(time.Minute))
+	started := make(chan struct{})
+	block := make(chan struct{})
+	defer close(block)
+	eventBus.Register([]*Event{{
+		Name: "charge_card",
+		Handler: func(payload interface{}) (interface{}, error) {
+			close(started)
+			<-block
+			return nil, nil
+		},
+	}})
+
+	envelope := NewEnvelope("charge_card", 100)
+	eventBus.processEnvelope(envelope)
+	<-started
+
+	reopened, err := NewFileDedupStore(dir, time.Hour)
+	assert.NoError(t, err)
+	added, err := reopened.Add(dedupKey(envelope))
+	assert.NoError(t, err)
+	assert.False(t, added, "A chave é gravada antes do handler: uma queda durante a execução descarta a reentrega (no máximo uma vez)")
+}
@@ -0,0 +1,157 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const HeaderAttempt = "attempt"
+
+type ErrorStage string
+
+const (
+	StagePublish  ErrorStage = "publish"
+	StageDispatch ErrorStage = "dispatch"
+	StageHandler  ErrorStage = "handler"
+	StageConsume  ErrorStage = "consume"
+	StageSpill    ErrorStage = "spill"
+	StageSchedule ErrorStage = "schedule"
+)
+
+var (
+	ErrPublishFailed  = errors.New("publish failed")
+	ErrDispatchFailed = errors.New("dispatch failed")
+	ErrHandlerFailed  = errors.New("handler failed")
+	ErrConsumeFailed  = errors.New("consume failed")
+	ErrSpillFailed    = errors.New("spill failed")
+	ErrScheduleFailed = errors.New("schedule failed")
+)
+
+func (s ErrorStage) sentinel() error {
+	switch s {
+	case StagePublish:
+		return ErrPublishFailed
+	case StageDispatch:
+		return ErrDispatchFailed
+	case StageHandler:
+		return ErrHandlerFailed
+	case StageConsume:
+		return ErrConsumeFailed
+	case StageSpill:
+		return ErrSpillFailed
+	case StageSchedule:
+		return ErrScheduleFailed
+	default:
+		return nil
+	}
+}
+
+type EventError struct {
+	Stage    ErrorStage
+	Envelope *EventPayload
+	Attempt  int
+	Err      error
+}
+
+func (e *EventError) EventName() string {
+	if e.Envelope == nil {
+		return ""
+	}
+	return e.Envelope.Name
+}
+
+func (e *EventError) Error() string {
+	if e.Envelope == nil {
+		return fmt.Sprintf("%s failed: %v", e.Stage, e.Err)
+	}
+	return fmt.Sprintf("%s failed for event %s: %v", e.Stage, e.Envelope.Name, e.Err)
+}
+
+func (e *EventError) Unwrap() error {
+	return e.Err
+}
+
+func (e *EventError) Is(target error) bool {
+	sentinel := e.Stage.sentinel()
+	return sentinel != nil && target == sentinel
+}
+
+type PanicError struct {
+	Value interface{}
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("recovered from panic: %v", e.Value)
+}
+
+type ErrorInfo struct {
+	Stage     ErrorStage
+	EventName string
+	Envelope  *EventPayload
+	Attempt   int
+	Err       error
+}
+
+func newErrorInfo(err error) ErrorInfo {
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) {
+		return ErrorInfo{Stage: StageConsume, Attempt: 1, Err: err}
+	}
+	return ErrorInfo{
+		Stage:     eventErr.Stage,
+		EventName: eventErr.EventName(),
+		Envelope:  eventErr.Envelope,
+		Attempt:   eventErr.Attempt,
+		Err:       eventErr.Err,
+	}
+}
+
+func (eb *EventBus) OnError(hook func(ctx context.Context, info ErrorInfo)) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.errorHooks = append(eb.errorHooks, hook)
+}
+
+func (eb *EventBus) DroppedErrors() uint64 {
+	return atomic.LoadUint64(&eb.droppedErrors)
+}
+
+func (ep *EventPayload) attempt() int {
+	if ep == nil {
+		return 1
+	}
+	if attempt, err := strconv.Atoi(ep.Headers[HeaderAttempt]); err == nil && attempt > 0 {
+		return attempt
+	}
+	return 1
+}
+
+func (eb *EventBus) reportError(ctx context.Context, stage ErrorStage, envelope *EventPayload, err error) {
+	eventErr := &EventError{Stage: stage, Envelope: envelope, Attempt: envelope.attempt(), Err: err}
+	eb.logger.ErrorContext(ctx, string(stage)+" failed", eb.logAttrs(ctx, envelope, slog.Any("error", err))...)
+	eb.notifyError(ctx, newErrorInfo(eventErr))
+
+	select {
+	case eb.errorCallback <- eventErr:
+	default:
+		atomic.AddUint64(&eb.droppedErrors, 1)
+		eb.errorCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("type", "error_queue_full"), attribute.String("event_name", eventErr.EventName())))
+	}
+}
+
+func (eb *EventBus) notifyError(ctx context.Context, info ErrorInfo) {
+	eb.mutex.Lock()
+	hooks := make([]func(context.Context, ErrorInfo), len(eb.errorHooks))
+	copy(hooks, eb.errorHooks)
+	eb.mutex.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx, info)
+	}
+}
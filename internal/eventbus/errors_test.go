@@ -0,0 +1,125 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventErrorWrapping(t *testing.T) {
+	cause := errors.New("boom")
+	err := error(&EventError{Stage: StageHandler, Envelope: &EventPayload{Name: "order_created"}, Attempt: 1, Err: cause})
+
+	assert.EqualError(t, err, "handler failed for event order_created: boom")
+	assert.ErrorIs(t, err, ErrHandlerFailed, "O erro deve ser classificado pelo estágio")
+	assert.NotErrorIs(t, err, ErrPublishFailed)
+	assert.ErrorIs(t, err, cause, "A causa original deve ser preservada")
+
+	var eventErr *EventError
+	assert.True(t, errors.As(err, &eventErr))
+	assert.Equal(t, "order_created", eventErr.EventName())
+}
+
+func TestNewErrorInfoFromUnstructuredError(t *testing.T) {
+	info := newErrorInfo(errors.New("connection reset"))
+	assert.Equal(t, StageConsume, info.Stage, "Erros do broker sem contexto devem ser classificados como consumo")
+	assert.Empty(t, info.EventName)
+	assert.EqualError(t, info.Err, "connection reset")
+}
+
+func TestOnErrorReceivesContext(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	infos := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) {
+		infos <- info
+	})
+	eventBus.Register([]*Event{{
+		Name: "error_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			return nil, errors.New("handler error")
+		},
+	}})
+
+	eventBus.ProcessEvent("error_event", "payload")
+
+	select {
+	case info := <-infos:
+		assert.Equal(t, StageHandler, info.Stage)
+		assert.Equal(t, "error_event", info.EventName)
+		assert.Equal(t, "payload", info.Envelope.Payload)
+		assert.Equal(t, 1, info.Attempt)
+		assert.EqualError(t, info.Err, "handler error")
+	case <-time.After(time.Second):
+		t.Fatal("O hook OnError deveria ter sido chamado")
+	}
+}
+
+func TestOnErrorReportsMissingHandlers(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	var info ErrorInfo
+	eventBus.OnError(func(ctx context.Context, i ErrorInfo) {
+		info = i
+	})
+
+	eventBus.ProcessEvent("unknown_event", nil)
+
+	assert.Equal(t, StageDispatch, info.Stage)
+	assert.Equal(t, "unknown_event", info.EventName)
+}
+
+func TestHandlerPanicIsRecovered(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	eventBus.Register([]*Event{{
+		Name: "panic_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			panic("kaboom")
+		},
+	}})
+
+	eventBus.ProcessEvent("panic_event", nil)
+
+	select {
+	case err := <-eventBus.errorCallback:
+		var panicErr *PanicError
+		assert.True(t, errors.As(err, &panicErr))
+		assert.Equal(t, "kaboom", panicErr.Value)
+		assert.ErrorIs(t, err, ErrHandlerFailed)
+	case <-time.After(time.Second):
+		t.Fatal("O pânico do handler deveria ter sido reportado")
+	}
+}
+
+func TestErrorDeliveryDoesNotBlock(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{ErrorQueueSize: 1})
+
+	done := make(chan struct{})
+	go func() {
+		for i := 0; i < 3; i++ {
+			eventBus.reportError(context.Background(), StagePublish, &EventPayload{Name: "test_event"}, errors.New("broker down"))
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("O envio de erros não deve bloquear quando a fila está cheia")
+	}
+	assert.Len(t, eventBus.errorCallback, 1)
+	assert.Equal(t, uint64(2), eventBus.DroppedErrors(), "Erros excedentes devem ser contabilizados")
+}
+
+func TestOnErrorReportsAttemptFromEnvelope(t *testing.T) {
+	eventBus, _ := New()
+	infos := make(chan ErrorInfo, 2)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { infos <- info })
+
+	eventBus.reportError(context.Background(), StageHandler, NewEnvelope("order_created", nil, WithHeader(HeaderAttempt, "3")), errors.New("handler error"))
+	eventBus.reportError(context.Background(), StageConsume, nil, errors.New("broker error"))
+
+	assert.Equal(t, 3, (<-infos).Attempt, "O attempt deve vir do envelope")
+	assert.Equal(t, 1, (<-infos).Attempt, "Sem envelope o attempt deve ser 1")
+}
@@ -1,8 +1,13 @@
 package eventbus
 
+import "context"
+
 type Event struct {
-	Name    string
-	Saga    *string
-	Next    *Event
-	Handler func(payload interface{}) (interface{}, error)
+	ID             string
+	Name           string
+	Saga           *string
+	Next           *Event
+	Handler        func(payload interface{}) (interface{}, error)
+	HandlerContext func(ctx context.Context, payload interface{}) (interface{}, error)
+	SideEffects    bool
 }
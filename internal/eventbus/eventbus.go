@@ -2,8 +2,12 @@ package eventbus
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.opentelemetry.io/otel"
@@ -13,14 +17,68 @@ import (
 	"go.opentelemetry.io/otel/trace"
 )
 
+const DefaultTopic = "event_topic"
+
 type EventBroker interface {
 	Publish(eventPayload *EventPayload, topic string) error
 	Consume(responseQueue chan *EventPayload, errorCallback chan error) (*EventPayload, error)
 }
 
+type BatchPublisher interface {
+	PublishBatch(ctx context.Context, topic string, eventPayloads []*EventPayload) []error
+}
+
 type EventPayload struct {
-	Name    string
-	Payload interface{}
+	ID            string
+	CorrelationID string
+	Name          string
+	Key           string
+	Priority      Priority
+	Headers       map[string]string
+	Payload       interface{}
+	future        *PublishFuture
+}
+
+type PublishOption func(*EventPayload)
+
+func WithOrderingKey(key string) PublishOption {
+	return func(ep *EventPayload) {
+		ep.Key = key
+	}
+}
+
+func WithHeader(key, value string) PublishOption {
+	return func(ep *EventPayload) {
+		if ep.Headers == nil {
+			ep.Headers = make(map[string]string)
+		}
+		ep.Headers[key] = value
+	}
+}
+
+func NewEnvelope(name string, payload interface{}, opts ...PublishOption) *EventPayload {
+	id := newID()
+	envelope := &EventPayload{ID: id, CorrelationID: id, Name: name, Payload: payload}
+	for _, opt := range opts {
+		opt(envelope)
+	}
+	return envelope
+}
+
+func (ep *EventPayload) child(name string, payload interface{}) *EventPayload {
+	return &EventPayload{
+		ID:            newID(),
+		CorrelationID: ep.CorrelationID,
+		Name:          name,
+		Key:           ep.Key,
+		Payload:       payload,
+	}
+}
+
+func (ep *EventPayload) confirm(err error) {
+	if ep.future != nil {
+		ep.future.resolve(err)
+	}
 }
 
 type EventBusConfig struct {
@@ -28,79 +86,191 @@ type EventBusConfig struct {
 	ResponseQueueSize int
 	ErrorQueueSize    int
 	WorkerPoolSize    int
+	PendingQueueSize  int
 	BatchSize         int
+	FlushInterval     time.Duration
 	Timeout           time.Duration
+	OverflowPolicy    OverflowPolicy
+	Priorities        map[string]Priority
+	PriorityWeights   map[Priority]int
+	ScheduleStore     ScheduleStore
+	CronStateStore    CronStateStore
+	DedupStore        DedupStore
+	CircuitBreakers   map[string]CircuitBreakerConfig
+	PublishRateLimits map[string]RateLimit
+	HandlerRateLimits map[string]RateLimit
+	Bulkheads         map[string]int
+	EventBulkheads    map[string]string
+	EventConcurrency  map[string]int
+	Adaptive          *AdaptiveConcurrency
+	HandlerTimeouts   map[string]time.Duration
+	SagaOnTimeout     bool
+	SpillDir          string
+	Codec             Codec
+	Logger            *slog.Logger
 }
 
 type EventBus struct {
-	mutex          *sync.Mutex
-	onceStart      *sync.Once
-	onceStop       *sync.Once
-	stopChannel    chan struct{}
-	requestQueue   chan *EventPayload
-	responseQueue  chan *EventPayload
-	eventRegistry  *EventRegistry
-	errorCallback  chan error
-	tracer         trace.Tracer
-	meter          metric.Meter
-	eventBroker    EventBroker
-	workerPool     chan struct{}
-	batch          []*EventPayload
-	config         EventBusConfig
-	eventCache     map[string][]*Event
-	publishCounter metric.Int64Counter
-	processCounter metric.Int64Counter
-	publishLatency metric.Float64Histogram
-	processLatency metric.Float64Histogram
-	queueSize      metric.Int64Gauge
-	errorCounter   metric.Int64Counter
+	mutex              *sync.Mutex
+	onceStart          *sync.Once
+	onceStop           *sync.Once
+	started            atomic.Bool
+	stopChannel        chan struct{}
+	stopMutex          *sync.RWMutex
+	requestQueue       chan *EventPayload
+	responseQueue      chan *EventPayload
+	requestLanes       map[Priority]chan *EventPayload
+	responseLanes      map[Priority]chan *EventPayload
+	requestScheduler   *laneScheduler
+	responseScheduler  *laneScheduler
+	wakeup             chan struct{}
+	eventRegistry      *EventRegistry
+	errorCallback      chan error
+	errorHooks         []func(context.Context, ErrorInfo)
+	publishMiddlewares []PublishMiddleware
+	handlerMiddlewares []HandlerMiddleware
+	droppedErrors      uint64
+	tracer             trace.Tracer
+	meter              metric.Meter
+	logger             *slog.Logger
+	name               string
+	eventBroker        EventBroker
+	workerPool         chan struct{}
+	pendingHandlers    chan struct{}
+	keyedExecutor      *keyedExecutor
+	batch              []*EventPayload
+	spill              *diskSpill
+	scheduler          *delayScheduler
+	cron               *cronRunner
+	replies            *replyRouter
+	breakers           map[string]*circuitBreaker
+	publishLimits      map[string]*tokenBucket
+	handlerLimits      map[string]*tokenBucket
+	eventBulkheads     map[string][]*bulkhead
+	adaptive           *adaptiveLimiter
+	config             EventBusConfig
+	eventCache         map[string][]*Event
+	publishCounter     metric.Int64Counter
+	processCounter     metric.Int64Counter
+	publishLatency     metric.Float64Histogram
+	processLatency     metric.Float64Histogram
+	queueSize          metric.Int64Gauge
+	errorCounter       metric.Int64Counter
+	overflowCounter    metric.Int64Counter
+	duplicateCounter   metric.Int64Counter
+	breakerState       metric.Int64Gauge
+	breakerRejected    metric.Int64Counter
+	throttledCounter   metric.Int64Counter
+	bulkheadQueued     metric.Int64UpDownCounter
+	bulkheadActive     metric.Int64UpDownCounter
+	bulkheadWait       metric.Float64Histogram
+	concurrencyLimit   metric.Int64Gauge
+	timeoutCounter     metric.Int64Counter
 }
 
-func NewEventBus(eventBroker EventBroker, tracer trace.Tracer, config EventBusConfig) (*EventBus, error) {
-	if tracer == nil {
-		tracer = otel.Tracer("noop")
-	}
-	if config.RequestQueueSize == 0 {
-		config.RequestQueueSize = 100
-	}
-	if config.ResponseQueueSize == 0 {
-		config.ResponseQueueSize = 100
-	}
-	if config.ErrorQueueSize == 0 {
-		config.ErrorQueueSize = 100
+func New(opts ...Option) (*EventBus, error) {
+	settings := newSettings(EventBusConfig{})
+	for _, opt := range opts {
+		if err := opt(settings); err != nil {
+			return nil, err
+		}
 	}
-	if config.WorkerPoolSize == 0 {
-		config.WorkerPoolSize = 10
+	return newEventBus(settings)
+}
+
+func NewEventBus(eventBroker EventBroker, tracer trace.Tracer, config EventBusConfig, opts ...Option) (*EventBus, error) {
+	settings := newSettings(config)
+	settings.eventBroker = eventBroker
+	settings.tracer = tracer
+	for _, opt := range opts {
+		if err := opt(settings); err != nil {
+			return nil, err
+		}
 	}
-	if config.BatchSize == 0 {
-		config.BatchSize = 10
+	return newEventBus(settings)
+}
+
+func newEventBus(settings *settings) (*EventBus, error) {
+	config := settings.config
+	if err := config.validate(); err != nil {
+		return nil, err
 	}
-	if config.Timeout == 0 {
-		config.Timeout = 5 * time.Second
+
+	eventBroker := settings.eventBroker
+	tracer := settings.tracer
+	if tracer == nil {
+		if settings.tracerProvider != nil {
+			tracer = settings.tracerProvider.Tracer("eventbus")
+		} else {
+			tracer = otel.Tracer("noop")
+		}
 	}
 
 	eventRegistry := NewEventRegistry()
-	meter := otel.Meter("eventbus")
+	meterProvider := settings.meterProvider
+	if meterProvider == nil {
+		meterProvider = otel.GetMeterProvider()
+	}
+	meter := meterProvider.Meter("eventbus")
+
+	poolSize := config.WorkerPoolSize
+	if config.Adaptive != nil {
+		poolSize = config.Adaptive.Max
+	}
 
 	eventBus := EventBus{
-		mutex:         &sync.Mutex{},
-		onceStart:     &sync.Once{},
-		onceStop:      &sync.Once{},
-		stopChannel:   make(chan struct{}),
-		requestQueue:  make(chan *EventPayload, config.RequestQueueSize),
-		responseQueue: make(chan *EventPayload, config.ResponseQueueSize),
-		errorCallback: make(chan error, config.ErrorQueueSize),
-		eventRegistry: eventRegistry,
-		eventBroker:   eventBroker,
-		tracer:        tracer,
-		meter:         meter,
-		workerPool:    make(chan struct{}, config.WorkerPoolSize),
-		batch:         make([]*EventPayload, 0, config.BatchSize),
-		config:        config,
-		eventCache:    make(map[string][]*Event),
+		mutex:             &sync.Mutex{},
+		onceStart:         &sync.Once{},
+		onceStop:          &sync.Once{},
+		stopChannel:       make(chan struct{}),
+		stopMutex:         &sync.RWMutex{},
+		requestLanes:      newLanes(config.RequestQueueSize),
+		responseLanes:     newLanes(config.ResponseQueueSize),
+		requestScheduler:  newLaneScheduler(config.PriorityWeights),
+		responseScheduler: newLaneScheduler(config.PriorityWeights),
+		wakeup:            make(chan struct{}, 1),
+		errorCallback:     make(chan error, config.ErrorQueueSize),
+		eventRegistry:     eventRegistry,
+		eventBroker:       eventBroker,
+		tracer:            tracer,
+		meter:             meter,
+		logger:            config.Logger,
+		name:              settings.name,
+		workerPool:        make(chan struct{}, poolSize),
+		pendingHandlers:   make(chan struct{}, config.PendingQueueSize),
+		keyedExecutor:     newKeyedExecutor(),
+		batch:             make([]*EventPayload, 0, config.BatchSize),
+		config:            config,
+		eventCache:        make(map[string][]*Event),
 	}
 
+	eventBus.requestQueue = eventBus.requestLanes[PriorityNormal]
+	eventBus.responseQueue = eventBus.responseLanes[PriorityNormal]
+
 	var err error
+	eventBus.cron = newCronRunner(config.CronStateStore)
+	eventBus.replies = newReplyRouter()
+	eventBus.breakers = make(map[string]*circuitBreaker, len(config.CircuitBreakers))
+	for name, breakerConfig := range config.CircuitBreakers {
+		eventBus.breakers[name] = newCircuitBreaker(breakerConfig)
+	}
+	eventBus.publishLimits = newTokenBuckets(config.PublishRateLimits)
+	eventBus.handlerLimits = newTokenBuckets(config.HandlerRateLimits)
+	eventBus.eventBulkheads = newBulkheads(config)
+	if config.Adaptive != nil {
+		eventBus.adaptive = newAdaptiveLimiter(*config.Adaptive, config.WorkerPoolSize)
+	}
+	eventBus.scheduler, err = newDelayScheduler(config.ScheduleStore)
+	if err != nil {
+		return nil, err
+	}
+	if config.OverflowPolicy == OverflowSpill {
+		eventBus.spill, err = newDiskSpill(config.SpillDir, config.Codec)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	eventBus.publishCounter, err = meter.Int64Counter("eventbus.publish.count", metric.WithDescription("Number of events published"))
 	if err != nil {
 		return nil, err
@@ -125,54 +295,121 @@ func NewEventBus(eventBroker EventBroker, tracer trace.Tracer, config EventBusCo
 	if err != nil {
 		return nil, err
 	}
+	eventBus.overflowCounter, err = meter.Int64Counter("eventbus.overflow", metric.WithDescription("Number of publishes that hit a full request queue, by policy outcome"))
+	if err != nil {
+		return nil, err
+	}
+
+	eventBus.duplicateCounter, err = meter.Int64Counter("eventbus.duplicates", metric.WithDescription("Number of redelivered events skipped by the dedup store"))
+	if err != nil {
+		return nil, err
+	}
+	eventBus.breakerState, err = meter.Int64Gauge("eventbus.circuit_breaker.state", metric.WithDescription("Circuit breaker state per event (0 closed, 1 open, 2 half-open)"))
+	if err != nil {
+		return nil, err
+	}
+	eventBus.breakerRejected, err = meter.Int64Counter("eventbus.circuit_breaker.rejected", metric.WithDescription("Number of events short-circuited by an open breaker, by outcome"))
+	if err != nil {
+		return nil, err
+	}
+	eventBus.throttledCounter, err = meter.Int64Counter("eventbus.throttled", metric.WithDescription("Number of events delayed or rejected by a rate limit, by stage and outcome"))
+	if err != nil {
+		return nil, err
+	}
+	if err := eventBus.newBulkheadMetrics(meter); err != nil {
+		return nil, err
+	}
+	eventBus.concurrencyLimit, err = meter.Int64Gauge("eventbus.worker_pool.limit", metric.WithDescription("Current concurrency limit of the worker pool"))
+	if err != nil {
+		return nil, err
+	}
+	eventBus.timeoutCounter, err = meter.Int64Counter("eventbus.handler.timeouts", metric.WithDescription("Number of handler invocations abandoned after their timeout"))
+	if err != nil {
+		return nil, err
+	}
+	if eventBus.adaptive != nil {
+		eventBus.adaptConcurrency(context.Background(), eventBus.adaptive.limit)
+	}
 
 	return &eventBus, nil
 }
 
+func (eb *EventBus) metricAttrs(attrs ...attribute.KeyValue) metric.MeasurementOption {
+	return metric.WithAttributes(append([]attribute.KeyValue{attribute.String("eventbus.name", eb.name)}, attrs...)...)
+}
+
 func (eb *EventBus) Stop() {
 	eb.onceStop.Do(func() {
 		close(eb.stopChannel)
+		if eb.started.Load() {
+			<-eb.cron.done
+		} else {
+			eb.abandonPending()
+		}
+		eb.logger.Info("event bus stopped")
 	})
 }
 
 func (eb *EventBus) Start() *EventBus {
 	eb.onceStart.Do(func() {
+		eb.started.Store(true)
+		eb.logger.Info("event bus started",
+			slog.Int("worker_pool_size", eb.config.WorkerPoolSize),
+			slog.Int("batch_size", eb.config.BatchSize),
+			slog.Bool("broker", eb.eventBroker != nil),
+		)
 
 		go func() {
+			flushTicker := time.NewTicker(eb.config.FlushInterval)
+			defer flushTicker.Stop()
 			for {
 				select {
 				case <-eb.stopChannel:
+					eb.abandonPending()
 					return
-				case eventPayload := <-eb.requestQueue:
-					eb.batch = append(eb.batch, eventPayload)
-					if len(eb.batch) >= eb.config.BatchSize {
+				case <-flushTicker.C:
+					eb.drainSpill()
+					if len(eb.batch) > 0 {
 						eb.publishBatch()
 					}
-				case eventPayload := <-eb.responseQueue:
-					eb.ProcessEvent(eventPayload.Name, eventPayload.Payload)
 				case err := <-eb.errorCallback:
-					_, span := eb.tracer.Start(context.Background(), "errorCallback")
-					span.SetAttributes(attribute.String("error", err.Error()))
-					span.End()
-					fmt.Printf("Error processing event: %v\n", err)
-					eb.errorCounter.Add(context.Background(), 1, metric.WithAttributes(attribute.String("type", "callback")))
+					eb.handleCallbackError(err)
 				default:
+					dispatched := eb.dispatchRequest()
+					dispatched = eb.dispatchResponse() || dispatched
+					if dispatched {
+						continue
+					}
 					if eb.eventBroker != nil {
 						eb.eventBroker.Consume(eb.responseQueue, eb.errorCallback)
+						continue
+					}
+					select {
+					case <-eb.stopChannel:
+					case <-flushTicker.C:
+						eb.drainSpill()
+						if len(eb.batch) > 0 {
+							eb.publishBatch()
+						}
+					case err := <-eb.errorCallback:
+						eb.handleCallbackError(err)
+					case <-eb.wakeup:
 					}
 				}
 			}
 		}()
 
+		go eb.runScheduler()
+		go eb.runCron()
+		go eb.runAdaptive()
+
 		go func() {
 			for {
 				select {
 				case <-eb.stopChannel:
 					return
 				default:
-					eb.queueSize.Record(context.Background(), int64(len(eb.requestQueue)), metric.WithAttributes(attribute.String("queue", "request")))
-					eb.queueSize.Record(context.Background(), int64(len(eb.responseQueue)), metric.WithAttributes(attribute.String("queue", "response")))
-					eb.queueSize.Record(context.Background(), int64(len(eb.errorCallback)), metric.WithAttributes(attribute.String("queue", "error")))
+					eb.recordQueueSizes(context.Background())
 					time.Sleep(1 * time.Second)
 				}
 			}
@@ -181,48 +418,145 @@ func (eb *EventBus) Start() *EventBus {
 	return eb
 }
 
+func (eb *EventBus) handleCallbackError(err error) {
+	var eventErr *EventError
+	if !errors.As(err, &eventErr) {
+		eb.logger.Error("broker error", slog.Any("error", err))
+		eb.notifyError(context.Background(), newErrorInfo(err))
+	}
+	_, span := eb.tracer.Start(context.Background(), "errorCallback")
+	span.SetAttributes(attribute.String("error", err.Error()))
+	span.End()
+	eb.errorCounter.Add(context.Background(), 1, eb.metricAttrs(attribute.String("type", "callback")))
+}
+
 func (eb *EventBus) publishBatch() {
 	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
 	defer cancel()
-	_, span := eb.tracer.Start(ctx, "PublishBatch")
+	ctx, span := eb.tracer.Start(ctx, "PublishBatch")
 	span.SetAttributes(attribute.Int("batch_size", len(eb.batch)))
 	defer span.End()
 
 	start := time.Now()
 	if eb.eventBroker != nil {
-		for _, event := range eb.batch {
-			err := eb.eventBroker.Publish(event, "event_topic")
+		errs := eb.brokerPublish(ctx, DefaultTopic, eb.batch)
+		for i, err := range errs {
 			if err != nil {
-
-				eb.errorCallback <- fmt.Errorf("failed to publish message: %w", err)
-				eb.errorCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("type", "publish")))
+				eb.reportError(ctx, StagePublish, eb.batch[i], fmt.Errorf("failed to publish message: %w", err))
+				eb.errorCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("type", "publish"), attribute.String("event_name", eb.batch[i].Name)))
 				span.RecordError(err)
 			} else {
-				eb.publishCounter.Add(ctx, 1)
+				eb.publishCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("event_name", eb.batch[i].Name)))
+				eb.logger.DebugContext(ctx, "event published", eb.logAttrs(ctx, eb.batch[i])...)
 			}
+			eb.batch[i].confirm(err)
 		}
 	} else {
 		for _, event := range eb.batch {
-			eb.responseQueue <- event
-			eb.publishCounter.Add(ctx, 1)
+			eb.pushResponse(event)
+			eb.publishCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("event_name", event.Name)))
+			eb.logger.DebugContext(ctx, "event published", eb.logAttrs(ctx, event)...)
+			event.confirm(nil)
 		}
 	}
 	duration := time.Since(start).Seconds()
-	eb.publishLatency.Record(ctx, duration)
+	eb.publishLatency.Record(ctx, duration, eb.metricAttrs())
+	eb.batch = eb.batch[:0]
+}
+
+func (eb *EventBus) brokerPublish(ctx context.Context, topic string, eventPayloads []*EventPayload) []error {
+	errs := make([]error, len(eventPayloads))
+	if batchPublisher, ok := eb.eventBroker.(BatchPublisher); ok {
+		batchErrs := batchPublisher.PublishBatch(ctx, topic, eventPayloads)
+		switch len(batchErrs) {
+		case 0:
+		case len(eventPayloads):
+			copy(errs, batchErrs)
+		default:
+			for i := range errs {
+				errs[i] = fmt.Errorf("batch publisher returned %d results for %d events", len(batchErrs), len(eventPayloads))
+			}
+		}
+		return errs
+	}
+	for i, eventPayload := range eventPayloads {
+		errs[i] = eb.eventBroker.Publish(eventPayload, topic)
+	}
+	return errs
+}
+
+func (eb *EventBus) abandonPending() {
+	eb.stopMutex.Lock()
+	defer eb.stopMutex.Unlock()
+	for _, eventPayload := range eb.batch {
+		eventPayload.confirm(ErrBusStopped)
+	}
 	eb.batch = eb.batch[:0]
+	for _, lane := range eb.requestLanes {
+	drain:
+		for {
+			select {
+			case eventPayload := <-lane:
+				eventPayload.confirm(ErrBusStopped)
+			default:
+				break drain
+			}
+		}
+	}
+	if eb.spill != nil {
+		eb.spill.abandon(ErrBusStopped)
+	}
+}
+
+func (eb *EventBus) Publish(name string, payload interface{}, opts ...PublishOption) error {
+	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
+	defer cancel()
+	return eb.PublishContext(ctx, name, payload, opts...)
+}
+
+func (eb *EventBus) PublishContext(ctx context.Context, name string, payload interface{}, opts ...PublishOption) error {
+	return eb.publishEnvelope(ctx, NewEnvelope(name, payload, opts...))
+}
+
+func (eb *EventBus) PublishEnvelope(ctx context.Context, envelope *EventPayload) error {
+	return eb.publishEnvelope(ctx, envelope)
+}
+
+func (eb *EventBus) PublishAsync(name string, payload interface{}, opts ...PublishOption) *PublishFuture {
+	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
+	defer cancel()
+	return eb.publishAsync(ctx, name, payload, opts...)
 }
 
-func (eb *EventBus) Publish(name string, payload interface{}) error {
-	select {
-	case eb.requestQueue <- &EventPayload{Name: name, Payload: payload}:
-		return nil
-	default:
-		eb.errorCounter.Add(context.Background(), 1, metric.WithAttributes(attribute.String("type", "queue_full")))
-		return fmt.Errorf("request queue full")
+func (eb *EventBus) PublishSync(ctx context.Context, name string, payload interface{}, opts ...PublishOption) error {
+	return eb.publishAsync(ctx, name, payload, opts...).Wait(ctx)
+}
+
+func (eb *EventBus) publishAsync(ctx context.Context, name string, payload interface{}, opts ...PublishOption) *PublishFuture {
+	future := newPublishFuture()
+	envelope := NewEnvelope(name, payload, opts...)
+	envelope.future = future
+	if err := eb.publishEnvelope(ctx, envelope); err != nil {
+		future.resolve(err)
 	}
+	return future
 }
 
 func (eb *EventBus) ProcessEvent(eventName string, payload interface{}) {
+	eb.processEnvelope(NewEnvelope(eventName, payload))
+}
+
+func (eb *EventBus) processEnvelope(envelope *EventPayload) {
+	if envelope.Name == eb.replies.inbox {
+		eb.replies.deliver(envelope)
+		return
+	}
+	if strings.HasPrefix(envelope.Name, replyInboxPrefix) {
+		eb.logger.Debug("ignoring reply addressed to another bus", eb.logAttrs(context.Background(), envelope)...)
+		return
+	}
+
+	eventName := envelope.Name
 	ctx, span := eb.tracer.Start(context.Background(), "ProcessEvent")
 	span.SetAttributes(attribute.String("event_name", eventName))
 	defer span.End()
@@ -233,56 +567,99 @@ func (eb *EventBus) ProcessEvent(eventName string, payload interface{}) {
 		var err error
 		events, err = eb.eventRegistry.Get(eventName)
 		if err != nil {
-
-			eb.errorCallback <- err
 			eb.mutex.Unlock()
+			eb.reportError(ctx, StageDispatch, envelope, err)
 			return
 		}
 		eb.eventCache[eventName] = events
 	}
 	eb.mutex.Unlock()
 
+	if eb.isDuplicate(ctx, envelope) {
+		span.SetAttributes(attribute.Bool("duplicate", true))
+		return
+	}
+
+	ctx, allowed := eb.allowBreaker(ctx, span, envelope, len(events))
+	if !allowed {
+		eb.forget(ctx, envelope)
+		return
+	}
+
+	for _, event := range events {
+		if envelope.Key != "" {
+			eb.dispatchKeyed(ctx, envelope, event)
+			continue
+		}
+
+		eb.dispatch(ctx, envelope, event)
+	}
+}
+
+func (eb *EventBus) invokeHandler(ctx context.Context, envelope *EventPayload, e *Event) {
 	defer func() {
 		if r := recover(); r != nil {
-			eb.errorCallback <- fmt.Errorf("recovered from panic: %v", r)
+			err := &PanicError{Value: r}
+			eb.recordBreaker(ctx, envelope, err)
+			eb.reportError(ctx, StageHandler, envelope, err)
 		}
 	}()
 
-	for _, event := range events {
-
-		eb.workerPool <- struct{}{}
-		go func(e *Event) {
-			defer func() { <-eb.workerPool }()
-
-			ctx, cancel := context.WithTimeout(ctx, eb.config.Timeout)
-			defer cancel()
+	timeout := eb.handlerTimeout(e.Name)
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
-			_, eventSpan := eb.tracer.Start(ctx, "EventHandler", trace.WithAttributes(attribute.String("event_name", e.Name)))
-			defer eventSpan.End()
+	ctx, eventSpan := eb.tracer.Start(ctx, "EventHandler", trace.WithAttributes(attribute.String("event_name", e.Name)))
+	defer eventSpan.End()
+	ctx = context.WithValue(ctx, handlerIDKey{}, e.ID)
+	if envelope.Key != "" {
+		eventSpan.SetAttributes(attribute.String("ordering_key", envelope.Key))
+	}
 
-			start := time.Now()
-			eventSpan.AddEvent("Starting event processing")
-			output, err := e.Handler(payload)
-			eventSpan.AddEvent("Finished event processing")
-			duration := time.Since(start).Seconds()
-			eb.processLatency.Record(ctx, duration)
-			eb.processCounter.Add(ctx, 1)
+	start := time.Now()
+	eventSpan.AddEvent("Starting event processing")
+	handler := eb.handlerChain(func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+		if e.HandlerContext != nil {
+			return e.HandlerContext(ctx, envelope.Payload)
+		}
+		return e.Handler(envelope.Payload)
+	})
+	output, err := eb.callHandler(ctx, e, timeout, handler, envelope.clone())
+	eventSpan.AddEvent("Finished event processing")
+	if errors.Is(err, ErrHandlerSkipped) {
+		eventSpan.SetAttributes(attribute.Bool("skipped", true))
+		eb.releaseBreaker(ctx, envelope)
+		eb.logger.DebugContext(ctx, "handler skipped", eb.logAttrs(ctx, envelope, slog.Any("reason", err))...)
+		return
+	}
+	eb.observeHandler(time.Since(start), err)
+	duration := time.Since(start).Seconds()
+	eb.processLatency.Record(ctx, duration, eb.metricAttrs(attribute.String("event_name", e.Name)))
+	eb.processCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("event_name", e.Name)))
+	eb.recordBreaker(ctx, envelope, err)
+	replayed := envelope.replayed()
+	if !replayed {
+		eb.reply(ctx, envelope, output, err)
+	}
 
-			if err != nil {
-				eventSpan.RecordError(err)
-				eventSpan.SetStatus(codes.Error, err.Error())
-				eb.errorCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("type", "handler")))
-
-				if e.Saga != nil {
-					eb.requestQueue <- &EventPayload{Name: *e.Saga, Payload: output}
-				} else {
-					eb.errorCallback <- err
-				}
-			}
-			if e.Next != nil {
-				eb.requestQueue <- &EventPayload{Name: e.Next.Name, Payload: output}
-			}
-		}(event)
+	var timeoutErr *TimeoutError
+	timedOut := errors.As(err, &timeoutErr)
+	if err != nil {
+		eventSpan.RecordError(err)
+		eventSpan.SetStatus(codes.Error, err.Error())
+		eventSpan.SetAttributes(attribute.Bool("timed_out", timedOut))
+		eb.errorCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("type", "handler"), attribute.String("event_name", e.Name)))
+
+		if e.Saga != nil && !replayed && (!timedOut || eb.config.SagaOnTimeout) {
+			eb.logger.WarnContext(ctx, "saga triggered", eb.logAttrs(ctx, envelope, slog.String("saga", *e.Saga), slog.Any("error", err))...)
+			eb.pushRequest(envelope.child(*e.Saga, output))
+		} else {
+			eb.reportError(ctx, StageHandler, envelope, err)
+			eb.forget(ctx, envelope)
+		}
+	}
+	if e.Next != nil && !replayed {
+		eb.pushRequest(envelope.child(e.Next.Name, output))
 	}
 }
 
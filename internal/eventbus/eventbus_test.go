@@ -1,6 +1,8 @@
 package eventbus
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -18,6 +20,35 @@ func (m *MockEventBroker) Consume(responseQueue chan *EventPayload, errorCallbac
 	return nil, nil
 }
 
+type MockBatchBroker struct {
+	MockEventBroker
+	batches [][]*EventPayload
+	errs    []error
+}
+
+func (m *MockBatchBroker) PublishBatch(ctx context.Context, topic string, eventPayloads []*EventPayload) []error {
+	m.batches = append(m.batches, append([]*EventPayload(nil), eventPayloads...))
+	return m.errs
+}
+
+type CountingEventBroker struct {
+	MockEventBroker
+	published []*EventPayload
+}
+
+func (m *CountingEventBroker) Publish(eventPayload *EventPayload, topic string) error {
+	m.published = append(m.published, eventPayload)
+	return nil
+}
+
+type FailingEventBroker struct {
+	MockEventBroker
+}
+
+func (m *FailingEventBroker) Publish(eventPayload *EventPayload, topic string) error {
+	return errors.New("broker down")
+}
+
 func TestNewEventBus(t *testing.T) {
 	config := EventBusConfig{
 		RequestQueueSize:  10,
@@ -98,7 +129,8 @@ func TestErrorHandling(t *testing.T) {
 
 	select {
 	case err := <-testErrorChan:
-		assert.EqualError(t, err, "handler error", "O erro retornado pelo handler deve ser capturado")
+		assert.EqualError(t, err, "handler failed for event error_event: handler error", "O erro retornado pelo handler deve ser capturado")
+		assert.ErrorIs(t, err, ErrHandlerFailed)
 	case <-time.After(1 * time.Second):
 		t.Error("Esperava um erro no canal errorCallback, mas não foi recebido")
 	}
@@ -132,3 +164,109 @@ func TestStop(t *testing.T) {
 		t.Error("O canal stopChannel deveria estar fechado após Stop()")
 	}
 }
+
+func TestPublishBatchUsesBatchPublisher(t *testing.T) {
+	broker := &MockBatchBroker{}
+	eventBus, _ := NewEventBus(broker, nil, EventBusConfig{BatchSize: 2})
+
+	eventBus.batch = append(eventBus.batch, &EventPayload{Name: "a"}, &EventPayload{Name: "b"})
+	eventBus.publishBatch()
+
+	assert.Len(t, broker.batches, 1, "O lote deve ser publicado em uma única chamada")
+	assert.Len(t, broker.batches[0], 2)
+	assert.Empty(t, eventBus.batch, "O lote deve ser esvaziado após a publicação")
+	assert.Len(t, eventBus.errorCallback, 0)
+}
+
+func TestPublishBatchPartialFailure(t *testing.T) {
+	broker := &MockBatchBroker{errs: []error{nil, errors.New("broker down")}}
+	eventBus, _ := NewEventBus(broker, nil, EventBusConfig{BatchSize: 2})
+
+	eventBus.batch = append(eventBus.batch, &EventPayload{Name: "a"}, &EventPayload{Name: "b"})
+	eventBus.publishBatch()
+
+	assert.Len(t, eventBus.errorCallback, 1, "Apenas o evento com falha deve ser reportado")
+	err := <-eventBus.errorCallback
+	assert.EqualError(t, err, "publish failed for event b: failed to publish message: broker down")
+	assert.ErrorIs(t, err, ErrPublishFailed)
+}
+
+func TestPublishBatchMismatchedResults(t *testing.T) {
+	broker := &MockBatchBroker{errs: []error{nil}}
+	eventBus, _ := NewEventBus(broker, nil, EventBusConfig{BatchSize: 2})
+
+	eventBus.batch = append(eventBus.batch, &EventPayload{Name: "a"}, &EventPayload{Name: "b"})
+	eventBus.publishBatch()
+
+	assert.Len(t, eventBus.errorCallback, 2, "Todos os eventos devem ser reportados quando o resultado é inconsistente")
+}
+
+func TestPublishBatchFallsBackToSinglePublish(t *testing.T) {
+	broker := &CountingEventBroker{}
+	eventBus, _ := NewEventBus(broker, nil, EventBusConfig{BatchSize: 2})
+
+	eventBus.batch = append(eventBus.batch, &EventPayload{Name: "a"}, &EventPayload{Name: "b"})
+	eventBus.publishBatch()
+
+	assert.Len(t, broker.published, 2, "Cada evento deve ser publicado individualmente")
+	assert.Len(t, eventBus.errorCallback, 0)
+}
+
+func TestPublishSyncConfirmsDelivery(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{FlushInterval: 10 * time.Millisecond})
+	eventBus.Register([]*Event{{
+		Name: "test_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			return nil, nil
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := eventBus.PublishSync(ctx, "test_event", nil)
+	assert.NoError(t, err, "Um lote incompleto deve ser publicado após o FlushInterval")
+}
+
+func TestPublishSyncReportsBrokerFailure(t *testing.T) {
+	eventBus, _ := NewEventBus(&FailingEventBroker{}, nil, EventBusConfig{BatchSize: 1})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := eventBus.PublishSync(ctx, "test_event", nil)
+	assert.EqualError(t, err, "broker down")
+}
+
+func TestPublishAsyncQueueFull(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{RequestQueueSize: 1})
+
+	first := eventBus.PublishAsync("test_event", nil)
+	second := eventBus.PublishAsync("test_event", nil)
+
+	select {
+	case <-second.Done():
+		assert.EqualError(t, second.Err(), "request queue full")
+	default:
+		t.Fatal("O future deve ser resolvido imediatamente quando a fila está cheia")
+	}
+	select {
+	case <-first.Done():
+		t.Fatal("O primeiro future não deve ser resolvido antes da publicação")
+	default:
+	}
+}
+
+func TestStopResolvesPendingFutures(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{FlushInterval: time.Hour})
+	eventBus.Start()
+
+	future := eventBus.PublishAsync("test_event", nil)
+	eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, future.Wait(ctx), ErrBusStopped)
+}
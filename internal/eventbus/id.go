@@ -0,0 +1,14 @@
+package eventbus
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+func newID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,15 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewID(t *testing.T) {
+	first := newID()
+	second := newID()
+
+	assert.Len(t, first, 32)
+	assert.NotEqual(t, first, second, "Cada chamada deve gerar um identificador único")
+}
@@ -0,0 +1,69 @@
+package eventbus
+
+import (
+	"context"
+	"sync"
+)
+
+type runningHandlerKey struct{}
+
+type keyedExecutor struct {
+	mutex   *sync.Mutex
+	pending map[string][]func()
+}
+
+func newKeyedExecutor() *keyedExecutor {
+	return &keyedExecutor{
+		mutex:   &sync.Mutex{},
+		pending: make(map[string][]func()),
+	}
+}
+
+func (k *keyedExecutor) submit(key string, task func()) bool {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	if queue, active := k.pending[key]; active {
+		k.pending[key] = append(queue, task)
+		return false
+	}
+	k.pending[key] = []func(){}
+	return true
+}
+
+func (k *keyedExecutor) next(key string) func() {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	queue := k.pending[key]
+	if len(queue) == 0 {
+		delete(k.pending, key)
+		return nil
+	}
+	task := queue[0]
+	k.pending[key] = queue[1:]
+	return task
+}
+
+func (k *keyedExecutor) activeKeys() int {
+	k.mutex.Lock()
+	defer k.mutex.Unlock()
+	return len(k.pending)
+}
+
+func (eb *EventBus) dispatchKeyed(ctx context.Context, envelope *EventPayload, e *Event) {
+	key := envelope.Key
+	task := func() {
+		running := &sync.WaitGroup{}
+		eb.runWorker(context.WithValue(ctx, runningHandlerKey{}, running), envelope, e)
+		running.Wait()
+	}
+	eb.pendingHandlers <- struct{}{}
+	if !eb.keyedExecutor.submit(key, task) {
+		return
+	}
+
+	go func() {
+		for next := task; next != nil; next = eb.keyedExecutor.next(key) {
+			next()
+		}
+	}()
+}
@@ -0,0 +1,178 @@
+package eventbus
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyedExecutorQueuesWhileActive(t *testing.T) {
+	executor := newKeyedExecutor()
+	var calls []string
+
+	assert.True(t, executor.submit("order-1", func() { calls = append(calls, "first") }), "A primeira tarefa da chave deve iniciar imediatamente")
+	assert.False(t, executor.submit("order-1", func() { calls = append(calls, "second") }), "Tarefas seguintes devem aguardar")
+	assert.True(t, executor.submit("order-2", func() {}), "Chaves diferentes não devem bloquear umas às outras")
+
+	next := executor.next("order-1")
+	assert.NotNil(t, next)
+	next()
+	assert.Nil(t, executor.next("order-1"))
+	assert.Equal(t, []string{"second"}, calls)
+	assert.Equal(t, 1, executor.activeKeys(), "A chave sem tarefas pendentes deve ser liberada")
+}
+
+func TestProcessEventSerializesPerKey(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{WorkerPoolSize: 4})
+	var mutex sync.Mutex
+	seen := make(map[string][]int)
+	running := make(map[string]bool)
+	overlap := false
+	var wg sync.WaitGroup
+
+	eventBus.Register([]*Event{{
+		Name: "order_updated",
+		Handler: func(payload interface{}) (interface{}, error) {
+			defer wg.Done()
+			update := payload.(map[string]int)
+			key := "order-1"
+			if update["order"] == 2 {
+				key = "order-2"
+			}
+			mutex.Lock()
+			if running[key] {
+				overlap = true
+			}
+			running[key] = true
+			mutex.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mutex.Lock()
+			running[key] = false
+			seen[key] = append(seen[key], update["seq"])
+			mutex.Unlock()
+			return nil, nil
+		},
+	}})
+
+	for seq := 0; seq < 10; seq++ {
+		for _, order := range []int{1, 2} {
+			wg.Add(1)
+			key := "order-1"
+			if order == 2 {
+				key = "order-2"
+			}
+			eventBus.processEnvelope(NewEnvelope("order_updated", map[string]int{"order": order, "seq": seq}, WithOrderingKey(key)))
+		}
+	}
+	wg.Wait()
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.False(t, overlap, "Eventos com a mesma chave não devem executar em paralelo")
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, seen["order-1"], "A ordem deve ser preservada por chave")
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, seen["order-2"])
+	assert.Eventually(t, func() bool { return eventBus.keyedExecutor.activeKeys() == 0 }, time.Second, time.Millisecond)
+}
+
+func TestOrderingKeyPropagatesToBroker(t *testing.T) {
+	broker := &MockBatchBroker{}
+	eventBus, _ := NewEventBus(broker, nil, EventBusConfig{})
+
+	assert.NoError(t, eventBus.Publish("order_created", nil, WithOrderingKey("order-1"), WithHeader("tenant", "acme")))
+	eventBus.batch = append(eventBus.batch, <-eventBus.requestQueue)
+	eventBus.publishBatch()
+
+	if assert.Len(t, broker.batches, 1) {
+		assert.Equal(t, "order-1", broker.batches[0][0].Key, "A chave de ordenação deve ser enviada ao broker como chave de partição")
+		assert.Equal(t, "acme", broker.batches[0][0].Headers["tenant"])
+	}
+}
+
+func TestChildEnvelopeInheritsKey(t *testing.T) {
+	parent := NewEnvelope("create_order", nil, WithOrderingKey("order-1"))
+	child := parent.child("reserve_stock", nil)
+	assert.Equal(t, "order-1", child.Key)
+}
+
+func TestKeyedPendingQueueBlocksDispatcher(t *testing.T) {
+	eventBus, _ := New(WithPendingQueueSize(2), WithTimeout(time.Minute))
+	release := make(chan struct{})
+	started := make(chan int, 4)
+	eventBus.Register([]*Event{{
+		Name: "order_updated",
+		Handler: func(payload interface{}) (interface{}, error) {
+			started <- payload.(int)
+			<-release
+			return nil, nil
+		},
+	}})
+
+	eventBus.processEnvelope(NewEnvelope("order_updated", 0, WithOrderingKey("order-1")))
+	<-started
+	done := make(chan struct{})
+	go func() {
+		for i := 1; i < 4; i++ {
+			eventBus.processEnvelope(NewEnvelope("order_updated", i, WithOrderingKey("order-1")))
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+		t.Fatal("O despacho deve bloquear quando a fila de eventos pendentes da chave estiver cheia")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("O despacho deve continuar quando a chave avançar")
+	}
+	for i := 1; i < 4; i++ {
+		assert.Equal(t, i, <-started)
+	}
+}
+
+func TestKeyIsHeldUntilTimedOutHandlerReturns(t *testing.T) {
+	eventBus, _ := New(WithHandlerTimeout("order_updated", 20*time.Millisecond))
+	release := make(chan struct{})
+	var mutex sync.Mutex
+	running, overlap := 0, false
+	handled := make(chan int, 2)
+	eventBus.Register([]*Event{{
+		Name: "order_updated",
+		Handler: func(payload interface{}) (interface{}, error) {
+			mutex.Lock()
+			running++
+			overlap = overlap || running > 1
+			mutex.Unlock()
+			if payload.(int) == 1 {
+				<-release
+			}
+			mutex.Lock()
+			running--
+			mutex.Unlock()
+			handled <- payload.(int)
+			return nil, nil
+		},
+	}})
+
+	eventBus.processEnvelope(NewEnvelope("order_updated", 1, WithOrderingKey("order-1")))
+	eventBus.processEnvelope(NewEnvelope("order_updated", 2, WithOrderingKey("order-1")))
+	select {
+	case <-handled:
+		t.Fatal("O próximo evento da chave não deve executar enquanto o handler abandonado por timeout ainda roda")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(release)
+	assert.Equal(t, 1, <-handled)
+	assert.Equal(t, 2, <-handled)
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.False(t, overlap, "Eventos da mesma chave não devem se sobrepor após um timeout")
+}
@@ -0,0 +1,28 @@
+package eventbus
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func (eb *EventBus) logAttrs(ctx context.Context, envelope *EventPayload, attrs ...slog.Attr) []any {
+	args := make([]any, 0, len(attrs)+4)
+	if envelope != nil {
+		args = append(args, slog.String("event_name", envelope.Name))
+		if envelope.ID != "" {
+			args = append(args, slog.String("event_id", envelope.ID))
+		}
+		if envelope.CorrelationID != "" {
+			args = append(args, slog.String("correlation_id", envelope.CorrelationID))
+		}
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.HasTraceID() {
+		args = append(args, slog.String("trace_id", spanContext.TraceID().String()))
+	}
+	for _, attr := range attrs {
+		args = append(args, attr)
+	}
+	return args
+}
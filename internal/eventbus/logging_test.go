@@ -0,0 +1,132 @@
+package eventbus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type syncBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.buffer.Write(p)
+}
+
+func (b *syncBuffer) records() []map[string]interface{} {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(b.buffer.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err == nil {
+			records = append(records, record)
+		}
+	}
+	return records
+}
+
+func (b *syncBuffer) find(msg string) map[string]interface{} {
+	for _, record := range b.records() {
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func newTestLogger(buffer *syncBuffer) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buffer, &slog.HandlerOptions{Level: slog.LevelDebug}))
+}
+
+func TestLoggerLifecycle(t *testing.T) {
+	buffer := &syncBuffer{}
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{Logger: newTestLogger(buffer)})
+
+	eventBus.Start()
+	eventBus.Stop()
+
+	assert.NotNil(t, buffer.find("event bus started"), "O início do EventBus deve ser registrado")
+	assert.NotNil(t, buffer.find("event bus stopped"), "A parada do EventBus deve ser registrada")
+}
+
+func TestLoggerHandlerFailureAndSaga(t *testing.T) {
+	buffer := &syncBuffer{}
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{Logger: newTestLogger(buffer)})
+	saga := "compensate_order"
+	eventBus.Register([]*Event{{
+		Name: "create_order",
+		Saga: &saga,
+		Handler: func(payload interface{}) (interface{}, error) {
+			return nil, errors.New("out of stock")
+		},
+	}})
+
+	envelope := NewEnvelope("create_order", nil)
+	eventBus.processEnvelope(envelope)
+
+	var compensation *EventPayload
+	select {
+	case compensation = <-eventBus.requestQueue:
+	case <-time.After(time.Second):
+		t.Fatal("O evento de compensação deveria ter sido enfileirado")
+	}
+	assert.Equal(t, saga, compensation.Name)
+	assert.Equal(t, envelope.CorrelationID, compensation.CorrelationID, "A saga deve herdar o correlation ID")
+	assert.NotEqual(t, envelope.ID, compensation.ID)
+
+	record := buffer.find("saga triggered")
+	if assert.NotNil(t, record) {
+		assert.Equal(t, "WARN", record["level"])
+		assert.Equal(t, "create_order", record["event_name"])
+		assert.Equal(t, envelope.CorrelationID, record["correlation_id"])
+		assert.Equal(t, saga, record["saga"])
+	}
+}
+
+func TestLoggerPublishFailure(t *testing.T) {
+	buffer := &syncBuffer{}
+	eventBus, _ := NewEventBus(&FailingEventBroker{}, nil, EventBusConfig{Logger: newTestLogger(buffer)})
+
+	eventBus.batch = append(eventBus.batch, NewEnvelope("test_event", nil))
+	eventBus.publishBatch()
+
+	record := buffer.find("publish failed")
+	if assert.NotNil(t, record) {
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, "test_event", record["event_name"])
+		assert.Contains(t, record["error"], "broker down")
+	}
+}
+
+func TestLogAttrsIncludesTraceID(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	traceID := trace.TraceID{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID,
+		SpanID:  trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8},
+	}))
+
+	args := eventBus.logAttrs(ctx, &EventPayload{Name: "test_event", ID: "id", CorrelationID: "corr"})
+
+	assert.Contains(t, args, slog.String("event_name", "test_event"))
+	assert.Contains(t, args, slog.String("event_id", "id"))
+	assert.Contains(t, args, slog.String("correlation_id", "corr"))
+	assert.Contains(t, args, slog.String("trace_id", traceID.String()))
+}
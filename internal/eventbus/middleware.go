@@ -0,0 +1,97 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrPublishSkipped = errors.New("publish skipped by middleware")
+	ErrHandlerSkipped = errors.New("handler skipped by middleware")
+)
+
+type handlerIDKey struct{}
+
+type PublishFunc func(ctx context.Context, envelope *EventPayload) error
+
+type PublishMiddleware func(next PublishFunc) PublishFunc
+
+type HandlerFunc func(ctx context.Context, envelope *EventPayload) (interface{}, error)
+
+type HandlerMiddleware func(next HandlerFunc) HandlerFunc
+
+func (eb *EventBus) UsePublish(middlewares ...PublishMiddleware) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.publishMiddlewares = append(eb.publishMiddlewares, middlewares...)
+}
+
+func (eb *EventBus) UseHandler(middlewares ...HandlerMiddleware) {
+	eb.mutex.Lock()
+	defer eb.mutex.Unlock()
+	eb.handlerMiddlewares = append(eb.handlerMiddlewares, middlewares...)
+}
+
+func HandlerID(ctx context.Context) string {
+	id, _ := ctx.Value(handlerIDKey{}).(string)
+	return id
+}
+
+func (eb *EventBus) publishChain(terminal PublishFunc) PublishFunc {
+	eb.mutex.Lock()
+	middlewares := make([]PublishMiddleware, len(eb.publishMiddlewares))
+	copy(middlewares, eb.publishMiddlewares)
+	eb.mutex.Unlock()
+
+	next := terminal
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+	return next
+}
+
+func (eb *EventBus) handlerChain(terminal HandlerFunc) HandlerFunc {
+	eb.mutex.Lock()
+	middlewares := make([]HandlerMiddleware, len(eb.handlerMiddlewares))
+	copy(middlewares, eb.handlerMiddlewares)
+	eb.mutex.Unlock()
+
+	next := terminal
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		next = middlewares[i](next)
+	}
+	return next
+}
+
+func (eb *EventBus) publishEnvelope(ctx context.Context, envelope *EventPayload) error {
+	enqueued := false
+	future := envelope.future
+	publish := eb.publishChain(func(ctx context.Context, envelope *EventPayload) error {
+		if err := eb.throttle(ctx, eb.publishLimits, "publish", envelope); err != nil {
+			return err
+		}
+		if envelope.future == nil {
+			envelope.future = future
+		}
+		enqueued = true
+		return eb.enqueue(ctx, envelope)
+	})
+	if err := publish(ctx, envelope); err != nil {
+		return err
+	}
+	if !enqueued {
+		envelope.confirm(ErrPublishSkipped)
+	}
+	return nil
+}
+
+func (ep *EventPayload) clone() *EventPayload {
+	cloned := *ep
+	if ep.Headers != nil {
+		cloned.Headers = make(map[string]string, len(ep.Headers))
+		for key, value := range ep.Headers {
+			cloned.Headers[key] = value
+		}
+	}
+	return &cloned
+}
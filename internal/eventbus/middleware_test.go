@@ -0,0 +1,199 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishMiddlewareOrder(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	var calls []string
+	trace := func(name string) PublishMiddleware {
+		return func(next PublishFunc) PublishFunc {
+			return func(ctx context.Context, envelope *EventPayload) error {
+				calls = append(calls, name+":before")
+				err := next(ctx, envelope)
+				calls = append(calls, name+":after")
+				return err
+			}
+		}
+	}
+	eventBus.UsePublish(trace("first"), trace("second"))
+
+	assert.NoError(t, eventBus.Publish("test_event", nil))
+	assert.Equal(t, []string{"first:before", "second:before", "second:after", "first:after"}, calls,
+		"Os middlewares devem executar na ordem de registro")
+}
+
+func TestPublishMiddlewareMutatesEnvelope(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	eventBus.UsePublish(func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, envelope *EventPayload) error {
+			envelope.Headers = map[string]string{"tenant": "acme"}
+			envelope.Payload = "mutated"
+			return next(ctx, envelope)
+		}
+	})
+
+	assert.NoError(t, eventBus.Publish("test_event", "original"))
+
+	envelope := <-eventBus.requestQueue
+	assert.Equal(t, "acme", envelope.Headers["tenant"])
+	assert.Equal(t, "mutated", envelope.Payload)
+}
+
+func TestPublishMiddlewareReplacementKeepsFuture(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	eventBus.UsePublish(func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, envelope *EventPayload) error {
+			return next(ctx, NewEnvelope(envelope.Name, "replaced"))
+		}
+	})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, eventBus.PublishSync(ctx, "test_event", "original"), "O future deve acompanhar o envelope substituído pelo middleware")
+}
+
+func TestPublishMiddlewareShortCircuit(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	eventBus.UsePublish(func(next PublishFunc) PublishFunc {
+		return func(ctx context.Context, envelope *EventPayload) error {
+			if envelope.Payload == nil {
+				return errors.New("payload required")
+			}
+			if envelope.Payload == "skip" {
+				return nil
+			}
+			return next(ctx, envelope)
+		}
+	})
+
+	err := eventBus.Publish("test_event", nil)
+	assert.EqualError(t, err, "payload required")
+	assert.Len(t, eventBus.requestQueue, 0, "O evento rejeitado não deve ser enfileirado")
+
+	future := eventBus.PublishAsync("test_event", "skip")
+	assert.ErrorIs(t, future.Err(), ErrPublishSkipped, "O future deve ser resolvido quando o middleware interrompe a publicação")
+	assert.Len(t, eventBus.requestQueue, 0)
+}
+
+func TestHandlerMiddlewareWrapsHandler(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	var mutex sync.Mutex
+	var calls []string
+	record := func(call string) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		calls = append(calls, call)
+	}
+	received := make(chan interface{}, 1)
+	eventBus.Register([]*Event{{
+		Name: "test_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			record("handler")
+			received <- payload
+			return nil, nil
+		},
+	}})
+	eventBus.UseHandler(
+		func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+				record("outer")
+				return next(ctx, envelope)
+			}
+		},
+		func(next HandlerFunc) HandlerFunc {
+			return func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+				record("inner")
+				envelope.Payload = envelope.Payload.(string) + "!"
+				return next(ctx, envelope)
+			}
+		},
+	)
+
+	eventBus.ProcessEvent("test_event", "hello")
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, "hello!", payload, "O middleware deve poder alterar o envelope")
+	case <-time.After(time.Second):
+		t.Fatal("O handler deveria ter sido chamado")
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	assert.Equal(t, []string{"outer", "inner", "handler"}, calls)
+}
+
+func TestHandlerMiddlewareShortCircuit(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{})
+	called := make(chan struct{}, 1)
+	eventBus.Register([]*Event{{
+		Name: "test_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			called <- struct{}{}
+			return nil, nil
+		},
+	}})
+	eventBus.UseHandler(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+			if envelope.Headers["authorization"] == "" {
+				return nil, errors.New("unauthorized")
+			}
+			return next(ctx, envelope)
+		}
+	})
+
+	eventBus.ProcessEvent("test_event", nil)
+
+	select {
+	case err := <-eventBus.errorCallback:
+		assert.ErrorIs(t, err, ErrHandlerFailed)
+		assert.ErrorContains(t, err, "unauthorized")
+	case <-time.After(time.Second):
+		t.Fatal("O erro do middleware deveria ter sido reportado")
+	}
+	assert.Len(t, called, 0, "O handler não deve ser chamado quando o middleware interrompe a cadeia")
+}
+
+func TestHandlerMiddlewareSkipDoesNotChainOrReport(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	ids := make(chan string, 2)
+	eventBus.UseHandler(func(next HandlerFunc) HandlerFunc {
+		return func(ctx context.Context, envelope *EventPayload) (interface{}, error) {
+			if envelope.Name != "order_paid" {
+				return next(ctx, envelope)
+			}
+			ids <- HandlerID(ctx)
+			return nil, fmt.Errorf("already handled: %w", ErrHandlerSkipped)
+		}
+	})
+	var nextCalls int32
+	eventBus.Register([]*Event{
+		{ID: "charge", Name: "order_paid", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }, Next: &Event{Name: "order_charged"}},
+		{Name: "order_paid", Handler: func(payload interface{}) (interface{}, error) { return nil, nil }},
+		{Name: "order_charged", Handler: func(payload interface{}) (interface{}, error) { atomic.AddInt32(&nextCalls, 1); return nil, nil }},
+	})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	eventBus.ProcessEvent("order_paid", 1)
+	assert.ElementsMatch(t, []string{"charge", ""}, []string{<-ids, <-ids}, "HandlerID deve expor apenas o ID declarado no Event")
+	select {
+	case info := <-failures:
+		t.Fatalf("Handlers pulados não devem reportar erro, recebido %v", info.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	assert.Zero(t, atomic.LoadInt32(&nextCalls), "Handlers pulados não devem disparar o evento seguinte")
+}
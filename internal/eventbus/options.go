@@ -0,0 +1,498 @@
+package eventbus
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const defaultInstanceName = "default"
+
+type Option func(*settings) error
+
+type settings struct {
+	name           string
+	config         EventBusConfig
+	eventBroker    EventBroker
+	tracer         trace.Tracer
+	meterProvider  metric.MeterProvider
+	tracerProvider trace.TracerProvider
+}
+
+func newSettings(config EventBusConfig) *settings {
+	return &settings{
+		name:   defaultInstanceName,
+		config: config.withDefaults(),
+	}
+}
+
+func (c EventBusConfig) withDefaults() EventBusConfig {
+	if c.RequestQueueSize == 0 {
+		c.RequestQueueSize = 100
+	}
+	if c.ResponseQueueSize == 0 {
+		c.ResponseQueueSize = 100
+	}
+	if c.ErrorQueueSize == 0 {
+		c.ErrorQueueSize = 100
+	}
+	if c.WorkerPoolSize == 0 {
+		c.WorkerPoolSize = 10
+	}
+	if c.PendingQueueSize == 0 {
+		c.PendingQueueSize = 100
+	}
+	if c.BatchSize == 0 {
+		c.BatchSize = 10
+	}
+	if c.FlushInterval == 0 {
+		c.FlushInterval = 100 * time.Millisecond
+	}
+	if c.Timeout == 0 {
+		c.Timeout = 5 * time.Second
+	}
+	if c.Codec == nil {
+		c.Codec = JSONCodec{}
+	}
+	if c.PriorityWeights == nil {
+		c.PriorityWeights = defaultPriorityWeights()
+	}
+	if c.Logger == nil {
+		c.Logger = slog.Default()
+	}
+	if c.Adaptive != nil {
+		adaptive := c.Adaptive.withDefaults()
+		c.Adaptive = &adaptive
+	}
+	if c.CircuitBreakers != nil {
+		breakers := make(map[string]CircuitBreakerConfig, len(c.CircuitBreakers))
+		for name, breaker := range c.CircuitBreakers {
+			breakers[name] = breaker.withDefaults()
+		}
+		c.CircuitBreakers = breakers
+	}
+	return c
+}
+
+func (c EventBusConfig) validate() error {
+	if c.RequestQueueSize < 1 {
+		return fmt.Errorf("request queue size must be positive, got %d", c.RequestQueueSize)
+	}
+	if c.ResponseQueueSize < 1 {
+		return fmt.Errorf("response queue size must be positive, got %d", c.ResponseQueueSize)
+	}
+	if c.ErrorQueueSize < 1 {
+		return fmt.Errorf("error queue size must be positive, got %d", c.ErrorQueueSize)
+	}
+	if c.WorkerPoolSize < 1 {
+		return fmt.Errorf("worker pool size must be positive, got %d", c.WorkerPoolSize)
+	}
+	if c.PendingQueueSize < 1 {
+		return fmt.Errorf("pending queue size must be positive, got %d", c.PendingQueueSize)
+	}
+	if c.BatchSize < 1 {
+		return fmt.Errorf("batch size must be positive, got %d", c.BatchSize)
+	}
+	if c.FlushInterval <= 0 {
+		return fmt.Errorf("flush interval must be positive, got %s", c.FlushInterval)
+	}
+	if c.Timeout <= 0 {
+		return fmt.Errorf("timeout must be positive, got %s", c.Timeout)
+	}
+	if c.OverflowPolicy < OverflowReject || c.OverflowPolicy > OverflowSpill {
+		return fmt.Errorf("unknown overflow policy %s", c.OverflowPolicy)
+	}
+	if c.OverflowPolicy == OverflowSpill && c.SpillDir == "" {
+		return fmt.Errorf("spill directory is required for overflow policy %s", c.OverflowPolicy)
+	}
+	for name, priority := range c.Priorities {
+		if !priority.valid() {
+			return fmt.Errorf("unknown priority %s for event %s", priority, name)
+		}
+	}
+	for name, breaker := range c.CircuitBreakers {
+		if err := breaker.validate(name); err != nil {
+			return err
+		}
+	}
+	if err := validateBulkheads(c); err != nil {
+		return err
+	}
+	if c.Adaptive != nil {
+		if err := c.Adaptive.validate(); err != nil {
+			return err
+		}
+	}
+	for name, timeout := range c.HandlerTimeouts {
+		if timeout <= 0 {
+			return fmt.Errorf("handler timeout for event %s must be positive, got %s", name, timeout)
+		}
+	}
+	for name, limit := range c.PublishRateLimits {
+		if err := limit.validate(name); err != nil {
+			return err
+		}
+	}
+	for name, limit := range c.HandlerRateLimits {
+		if err := limit.validate(name); err != nil {
+			return err
+		}
+	}
+	for _, priority := range priorities {
+		if weight := c.PriorityWeights[priority]; weight < 1 {
+			return fmt.Errorf("weight for priority %s must be positive, got %d", priority, weight)
+		}
+	}
+	return nil
+}
+
+func WithName(name string) Option {
+	return func(s *settings) error {
+		if name == "" {
+			return errors.New("event bus name cannot be empty")
+		}
+		s.name = name
+		return nil
+	}
+}
+
+func WithBroker(eventBroker EventBroker) Option {
+	return func(s *settings) error {
+		if eventBroker == nil {
+			return errors.New("event broker cannot be nil")
+		}
+		s.eventBroker = eventBroker
+		return nil
+	}
+}
+
+func WithTracer(tracer trace.Tracer) Option {
+	return func(s *settings) error {
+		if tracer == nil {
+			return errors.New("tracer cannot be nil")
+		}
+		s.tracer = tracer
+		return nil
+	}
+}
+
+func WithMeterProvider(meterProvider metric.MeterProvider) Option {
+	return func(s *settings) error {
+		if meterProvider == nil {
+			return errors.New("meter provider cannot be nil")
+		}
+		s.meterProvider = meterProvider
+		return nil
+	}
+}
+
+func WithTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return func(s *settings) error {
+		if tracerProvider == nil {
+			return errors.New("tracer provider cannot be nil")
+		}
+		s.tracerProvider = tracerProvider
+		return nil
+	}
+}
+
+func WithRequestQueueSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("request queue size must be positive, got %d", size)
+		}
+		s.config.RequestQueueSize = size
+		return nil
+	}
+}
+
+func WithResponseQueueSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("response queue size must be positive, got %d", size)
+		}
+		s.config.ResponseQueueSize = size
+		return nil
+	}
+}
+
+func WithErrorQueueSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("error queue size must be positive, got %d", size)
+		}
+		s.config.ErrorQueueSize = size
+		return nil
+	}
+}
+
+func WithWorkerPoolSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("worker pool size must be positive, got %d", size)
+		}
+		s.config.WorkerPoolSize = size
+		return nil
+	}
+}
+
+func WithPendingQueueSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("pending queue size must be positive, got %d", size)
+		}
+		s.config.PendingQueueSize = size
+		return nil
+	}
+}
+
+func WithBatchSize(size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("batch size must be positive, got %d", size)
+		}
+		s.config.BatchSize = size
+		return nil
+	}
+}
+
+func WithFlushInterval(interval time.Duration) Option {
+	return func(s *settings) error {
+		if interval <= 0 {
+			return fmt.Errorf("flush interval must be positive, got %s", interval)
+		}
+		s.config.FlushInterval = interval
+		return nil
+	}
+}
+
+func WithTimeout(timeout time.Duration) Option {
+	return func(s *settings) error {
+		if timeout <= 0 {
+			return fmt.Errorf("timeout must be positive, got %s", timeout)
+		}
+		s.config.Timeout = timeout
+		return nil
+	}
+}
+
+func WithOverflowPolicy(policy OverflowPolicy) Option {
+	return func(s *settings) error {
+		if policy < OverflowReject || policy > OverflowSpill {
+			return fmt.Errorf("unknown overflow policy %s", policy)
+		}
+		s.config.OverflowPolicy = policy
+		return nil
+	}
+}
+
+func WithSpillDir(dir string) Option {
+	return func(s *settings) error {
+		if dir == "" {
+			return errors.New("spill directory cannot be empty")
+		}
+		s.config.SpillDir = dir
+		return nil
+	}
+}
+
+func WithCodec(codec Codec) Option {
+	return func(s *settings) error {
+		if codec == nil {
+			return errors.New("codec cannot be nil")
+		}
+		s.config.Codec = codec
+		return nil
+	}
+}
+
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *settings) error {
+		if logger == nil {
+			return errors.New("logger cannot be nil")
+		}
+		s.config.Logger = logger
+		return nil
+	}
+}
+
+func WithEventPriority(name string, priority Priority) Option {
+	return func(s *settings) error {
+		if !priority.valid() {
+			return fmt.Errorf("unknown priority %s for event %s", priority, name)
+		}
+		priorities := make(map[string]Priority, len(s.config.Priorities)+1)
+		for eventName, eventPriority := range s.config.Priorities {
+			priorities[eventName] = eventPriority
+		}
+		priorities[name] = priority
+		s.config.Priorities = priorities
+		return nil
+	}
+}
+
+func WithPriorityWeights(weights map[Priority]int) Option {
+	return func(s *settings) error {
+		for _, priority := range priorities {
+			if weights[priority] < 1 {
+				return fmt.Errorf("weight for priority %s must be positive, got %d", priority, weights[priority])
+			}
+		}
+		s.config.PriorityWeights = weights
+		return nil
+	}
+}
+
+func WithScheduleStore(store ScheduleStore) Option {
+	return func(s *settings) error {
+		if store == nil {
+			return errors.New("schedule store cannot be nil")
+		}
+		s.config.ScheduleStore = store
+		return nil
+	}
+}
+
+func WithCronStateStore(store CronStateStore) Option {
+	return func(s *settings) error {
+		if store == nil {
+			return errors.New("cron state store cannot be nil")
+		}
+		s.config.CronStateStore = store
+		return nil
+	}
+}
+
+func WithDedupStore(store DedupStore) Option {
+	return func(s *settings) error {
+		if store == nil {
+			return errors.New("dedup store cannot be nil")
+		}
+		s.config.DedupStore = store
+		return nil
+	}
+}
+
+func WithCircuitBreaker(name string, config CircuitBreakerConfig) Option {
+	return func(s *settings) error {
+		config = config.withDefaults()
+		if err := config.validate(name); err != nil {
+			return err
+		}
+		breakers := make(map[string]CircuitBreakerConfig, len(s.config.CircuitBreakers)+1)
+		for eventName, breaker := range s.config.CircuitBreakers {
+			breakers[eventName] = breaker
+		}
+		breakers[name] = config
+		s.config.CircuitBreakers = breakers
+		return nil
+	}
+}
+
+func WithPublishRateLimit(name string, limit RateLimit) Option {
+	return func(s *settings) error {
+		if err := limit.validate(name); err != nil {
+			return err
+		}
+		s.config.PublishRateLimits = withRateLimit(s.config.PublishRateLimits, name, limit)
+		return nil
+	}
+}
+
+func WithHandlerRateLimit(name string, limit RateLimit) Option {
+	return func(s *settings) error {
+		if err := limit.validate(name); err != nil {
+			return err
+		}
+		s.config.HandlerRateLimits = withRateLimit(s.config.HandlerRateLimits, name, limit)
+		return nil
+	}
+}
+
+func withRateLimit(limits map[string]RateLimit, name string, limit RateLimit) map[string]RateLimit {
+	merged := make(map[string]RateLimit, len(limits)+1)
+	for eventName, eventLimit := range limits {
+		merged[eventName] = eventLimit
+	}
+	merged[name] = limit
+	return merged
+}
+
+func WithBulkhead(name string, size int) Option {
+	return func(s *settings) error {
+		if size < 1 {
+			return fmt.Errorf("bulkhead %s size must be positive, got %d", name, size)
+		}
+		bulkheads := make(map[string]int, len(s.config.Bulkheads)+1)
+		for pool, poolSize := range s.config.Bulkheads {
+			bulkheads[pool] = poolSize
+		}
+		bulkheads[name] = size
+		s.config.Bulkheads = bulkheads
+		return nil
+	}
+}
+
+func WithEventBulkhead(name, bulkhead string) Option {
+	return func(s *settings) error {
+		assignments := make(map[string]string, len(s.config.EventBulkheads)+1)
+		for eventName, pool := range s.config.EventBulkheads {
+			assignments[eventName] = pool
+		}
+		assignments[name] = bulkhead
+		s.config.EventBulkheads = assignments
+		return nil
+	}
+}
+
+func WithEventConcurrency(name string, limit int) Option {
+	return func(s *settings) error {
+		if limit < 1 {
+			return fmt.Errorf("concurrency limit for event %s must be positive, got %d", name, limit)
+		}
+		limits := make(map[string]int, len(s.config.EventConcurrency)+1)
+		for eventName, eventLimit := range s.config.EventConcurrency {
+			limits[eventName] = eventLimit
+		}
+		limits[name] = limit
+		s.config.EventConcurrency = limits
+		return nil
+	}
+}
+
+func WithAdaptiveConcurrency(adaptive AdaptiveConcurrency) Option {
+	return func(s *settings) error {
+		adaptive = adaptive.withDefaults()
+		if err := adaptive.validate(); err != nil {
+			return err
+		}
+		s.config.Adaptive = &adaptive
+		return nil
+	}
+}
+
+func WithHandlerTimeout(name string, timeout time.Duration) Option {
+	return func(s *settings) error {
+		if timeout <= 0 {
+			return fmt.Errorf("handler timeout for event %s must be positive, got %s", name, timeout)
+		}
+		timeouts := make(map[string]time.Duration, len(s.config.HandlerTimeouts)+1)
+		for eventName, eventTimeout := range s.config.HandlerTimeouts {
+			timeouts[eventName] = eventTimeout
+		}
+		timeouts[name] = timeout
+		s.config.HandlerTimeouts = timeouts
+		return nil
+	}
+}
+
+func WithSagaOnTimeout(enabled bool) Option {
+	return func(s *settings) error {
+		s.config.SagaOnTimeout = enabled
+		return nil
+	}
+}
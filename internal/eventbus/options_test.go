@@ -0,0 +1,193 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func collectSums(t *testing.T, reader *sdkmetric.ManualReader, name string) []metricdata.DataPoint[int64] {
+	var resourceMetrics metricdata.ResourceMetrics
+	assert.NoError(t, reader.Collect(context.Background(), &resourceMetrics))
+	for _, scopeMetrics := range resourceMetrics.ScopeMetrics {
+		for _, m := range scopeMetrics.Metrics {
+			if m.Name == name {
+				if sum, ok := m.Data.(metricdata.Sum[int64]); ok {
+					return sum.DataPoints
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestOptionsRejectInvalidValues(t *testing.T) {
+	_, err := NewEventBus(nil, nil, EventBusConfig{}, WithName(""))
+	assert.EqualError(t, err, "event bus name cannot be empty")
+
+	_, err = NewEventBus(nil, nil, EventBusConfig{}, WithMeterProvider(nil))
+	assert.EqualError(t, err, "meter provider cannot be nil")
+
+	_, err = NewEventBus(nil, nil, EventBusConfig{}, WithTracerProvider(nil))
+	assert.EqualError(t, err, "tracer provider cannot be nil")
+}
+
+func TestWithMeterProviderIsolatesBuses(t *testing.T) {
+	ordersReader := sdkmetric.NewManualReader()
+	billingReader := sdkmetric.NewManualReader()
+	orders, err := NewEventBus(nil, nil, EventBusConfig{},
+		WithName("orders"),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(ordersReader))),
+	)
+	assert.NoError(t, err)
+	_, err = NewEventBus(nil, nil, EventBusConfig{},
+		WithName("billing"),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(billingReader))),
+	)
+	assert.NoError(t, err)
+
+	orders.batch = append(orders.batch, NewEnvelope("order_created", nil))
+	orders.publishBatch()
+
+	dataPoints := collectSums(t, ordersReader, "eventbus.publish.count")
+	if assert.Len(t, dataPoints, 1) {
+		assert.Equal(t, int64(1), dataPoints[0].Value)
+		name, _ := dataPoints[0].Attributes.Value(attribute.Key("eventbus.name"))
+		assert.Equal(t, "orders", name.AsString())
+		eventName, _ := dataPoints[0].Attributes.Value(attribute.Key("event_name"))
+		assert.Equal(t, "order_created", eventName.AsString())
+	}
+	assert.Empty(t, collectSums(t, billingReader, "eventbus.publish.count"), "Cada bus deve usar seu próprio MeterProvider")
+}
+
+func TestProcessMetricsHaveEventNameDimension(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{},
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	done := make(chan struct{})
+	eventBus.Register([]*Event{{
+		Name: "test_event",
+		Handler: func(payload interface{}) (interface{}, error) {
+			close(done)
+			return nil, nil
+		},
+	}})
+
+	eventBus.ProcessEvent("test_event", nil)
+	<-done
+
+	assert.Eventually(t, func() bool {
+		dataPoints := collectSums(t, reader, "eventbus.process.count")
+		if len(dataPoints) != 1 {
+			return false
+		}
+		name, _ := dataPoints[0].Attributes.Value(attribute.Key("eventbus.name"))
+		eventName, _ := dataPoints[0].Attributes.Value(attribute.Key("event_name"))
+		return name.AsString() == defaultInstanceName && eventName.AsString() == "test_event"
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestWithTracerProvider(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{},
+		WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+	)
+
+	eventBus.batch = append(eventBus.batch, NewEnvelope("test_event", nil))
+	eventBus.publishBatch()
+
+	spans := recorder.Ended()
+	if assert.Len(t, spans, 1) {
+		assert.Equal(t, "PublishBatch", spans[0].Name())
+	}
+}
+
+func TestNewUsesDefaults(t *testing.T) {
+	eventBus, err := New()
+	assert.NoError(t, err)
+	assert.Equal(t, 100, cap(eventBus.requestQueue))
+	assert.Equal(t, 100, cap(eventBus.responseQueue))
+	assert.Equal(t, 100, cap(eventBus.errorCallback))
+	assert.Equal(t, 10, cap(eventBus.workerPool))
+	assert.Equal(t, 100, cap(eventBus.pendingHandlers))
+	assert.Equal(t, 10, cap(eventBus.batch))
+	assert.Equal(t, 5*time.Second, eventBus.config.Timeout)
+	assert.Nil(t, eventBus.eventBroker)
+}
+
+func TestNewAppliesOptions(t *testing.T) {
+	broker := &MockEventBroker{}
+	codec := JSONCodec{}
+	eventBus, err := New(
+		WithBroker(broker),
+		WithRequestQueueSize(1),
+		WithResponseQueueSize(2),
+		WithErrorQueueSize(3),
+		WithWorkerPoolSize(4),
+		WithPendingQueueSize(6),
+		WithBatchSize(5),
+		WithFlushInterval(time.Second),
+		WithTimeout(time.Minute),
+		WithOverflowPolicy(OverflowSpill),
+		WithSpillDir(t.TempDir()),
+		WithCodec(codec),
+	)
+	assert.NoError(t, err)
+	assert.Same(t, broker, eventBus.eventBroker)
+	assert.Equal(t, 1, cap(eventBus.requestQueue))
+	assert.Equal(t, 2, cap(eventBus.responseQueue))
+	assert.Equal(t, 3, cap(eventBus.errorCallback))
+	assert.Equal(t, 4, cap(eventBus.workerPool))
+	assert.Equal(t, 6, cap(eventBus.pendingHandlers))
+	assert.Equal(t, 5, cap(eventBus.batch))
+	assert.Equal(t, time.Second, eventBus.config.FlushInterval)
+	assert.Equal(t, time.Minute, eventBus.config.Timeout)
+	assert.NotNil(t, eventBus.spill)
+}
+
+func TestNewRejectsInvalidOptions(t *testing.T) {
+	tests := []struct {
+		option Option
+		err    string
+	}{
+		{WithBroker(nil), "event broker cannot be nil"},
+		{WithTracer(nil), "tracer cannot be nil"},
+		{WithRequestQueueSize(0), "request queue size must be positive, got 0"},
+		{WithResponseQueueSize(-1), "response queue size must be positive, got -1"},
+		{WithErrorQueueSize(-1), "error queue size must be positive, got -1"},
+		{WithWorkerPoolSize(0), "worker pool size must be positive, got 0"},
+		{WithPendingQueueSize(0), "pending queue size must be positive, got 0"},
+		{WithBatchSize(-5), "batch size must be positive, got -5"},
+		{WithFlushInterval(0), "flush interval must be positive, got 0s"},
+		{WithTimeout(-time.Second), "timeout must be positive, got -1s"},
+		{WithOverflowPolicy(OverflowPolicy(42)), "unknown overflow policy OverflowPolicy(42)"},
+		{WithSpillDir(""), "spill directory cannot be empty"},
+		{WithCodec(nil), "codec cannot be nil"},
+		{WithLogger(nil), "logger cannot be nil"},
+	}
+	for _, test := range tests {
+		_, err := New(test.option)
+		assert.EqualError(t, err, test.err)
+	}
+}
+
+func TestNewValidatesCombinedOptions(t *testing.T) {
+	_, err := New(WithOverflowPolicy(OverflowSpill))
+	assert.EqualError(t, err, "spill directory is required for overflow policy spill")
+}
+
+func TestNewEventBusRejectsNegativeConfig(t *testing.T) {
+	_, err := NewEventBus(nil, nil, EventBusConfig{WorkerPoolSize: -1})
+	assert.EqualError(t, err, "worker pool size must be positive, got -1")
+
+	_, err = NewEventBus(nil, nil, EventBusConfig{Timeout: -time.Second})
+	assert.EqualError(t, err, "timeout must be positive, got -1s")
+}
@@ -0,0 +1,140 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type OverflowPolicy int
+
+const (
+	OverflowReject OverflowPolicy = iota
+	OverflowBlock
+	OverflowDropOldest
+	OverflowDropNewest
+	OverflowSpill
+)
+
+var (
+	ErrQueueFull    = errors.New("request queue full")
+	ErrEventDropped = errors.New("event dropped by overflow policy")
+)
+
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowReject:
+		return "reject"
+	case OverflowBlock:
+		return "block"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowSpill:
+		return "spill"
+	default:
+		return fmt.Sprintf("OverflowPolicy(%d)", int(p))
+	}
+}
+
+func (eb *EventBus) enqueue(ctx context.Context, eventPayload *EventPayload) error {
+	eb.stopMutex.RLock()
+	defer eb.stopMutex.RUnlock()
+	select {
+	case <-eb.stopChannel:
+		return ErrBusStopped
+	default:
+	}
+	if eb.spill != nil && eb.spill.Len() > 0 {
+		return eb.spillEvent(ctx, eventPayload)
+	}
+	lane := eb.requestLane(eventPayload)
+	defer eb.wake()
+	select {
+	case lane <- eventPayload:
+		return nil
+	default:
+	}
+
+	switch eb.config.OverflowPolicy {
+	case OverflowBlock:
+		select {
+		case lane <- eventPayload:
+			eb.recordOverflow(ctx, eventPayload, "blocked")
+			return nil
+		case <-ctx.Done():
+			eb.recordOverflow(ctx, eventPayload, "timeout")
+			return fmt.Errorf("%w: %w", ErrQueueFull, ctx.Err())
+		case <-eb.stopChannel:
+			return ErrBusStopped
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case dropped := <-lane:
+				dropped.confirm(ErrEventDropped)
+				eb.recordOverflow(ctx, dropped, "dropped_oldest")
+			default:
+			}
+			select {
+			case lane <- eventPayload:
+				return nil
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		eventPayload.confirm(ErrEventDropped)
+		eb.recordOverflow(ctx, eventPayload, "dropped_newest")
+		return ErrEventDropped
+	case OverflowSpill:
+		return eb.spillEvent(ctx, eventPayload)
+	}
+
+	eb.recordOverflow(ctx, eventPayload, "rejected")
+	eb.errorCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("type", "queue_full"), attribute.String("event_name", eventPayload.Name)))
+	return ErrQueueFull
+}
+
+func (eb *EventBus) spillEvent(ctx context.Context, eventPayload *EventPayload) error {
+	if err := eb.spill.Push(eventPayload); err != nil {
+		eb.recordOverflow(ctx, eventPayload, "spill_failed")
+		eb.errorCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("type", "spill"), attribute.String("event_name", eventPayload.Name)))
+		return err
+	}
+	eb.recordOverflow(ctx, eventPayload, "spilled")
+	return nil
+}
+
+func (eb *EventBus) drainSpill() {
+	if eb.spill == nil {
+		return
+	}
+	for i := 0; i < eb.config.RequestQueueSize; i++ {
+		eventPayload, err := eb.spill.Pop()
+		if err != nil {
+			eb.reportError(context.Background(), StageSpill, eventPayload, err)
+			eb.errorCounter.Add(context.Background(), 1, eb.metricAttrs(attribute.String("type", "spill")))
+		}
+		if eventPayload == nil {
+			if err != nil {
+				continue
+			}
+			return
+		}
+		eb.batch = append(eb.batch, eventPayload)
+		if len(eb.batch) >= eb.config.BatchSize {
+			eb.publishBatch()
+		}
+	}
+}
+
+func (eb *EventBus) recordOverflow(ctx context.Context, eventPayload *EventPayload, outcome string) {
+	eb.overflowCounter.Add(ctx, 1, eb.metricAttrs(
+		attribute.String("policy", eb.config.OverflowPolicy.String()),
+		attribute.String("outcome", outcome),
+		attribute.String("event_name", eventPayload.Name),
+	))
+}
@@ -0,0 +1,139 @@
+package eventbus
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOverflowRejectIsDefault(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{RequestQueueSize: 1})
+
+	assert.NoError(t, eventBus.Publish("test_event", nil))
+	err := eventBus.Publish("test_event", nil)
+	assert.ErrorIs(t, err, ErrQueueFull)
+}
+
+func TestOverflowBlockTimesOut(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		OverflowPolicy:   OverflowBlock,
+	})
+	assert.NoError(t, eventBus.Publish("test_event", nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := eventBus.PublishContext(ctx, "test_event", nil)
+	assert.ErrorIs(t, err, ErrQueueFull)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestOverflowBlockWaitsForSpace(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		OverflowPolicy:   OverflowBlock,
+	})
+	assert.NoError(t, eventBus.Publish("first", nil))
+
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		<-eventBus.requestQueue
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, eventBus.PublishContext(ctx, "second", nil))
+	assert.Equal(t, "second", (<-eventBus.requestQueue).Name)
+}
+
+func TestOverflowDropOldest(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		OverflowPolicy:   OverflowDropOldest,
+	})
+
+	oldest := eventBus.PublishAsync("first", nil)
+	assert.NoError(t, eventBus.Publish("second", nil))
+
+	assert.ErrorIs(t, oldest.Err(), ErrEventDropped, "O evento mais antigo deve ser descartado")
+	assert.Equal(t, "second", (<-eventBus.requestQueue).Name)
+}
+
+func TestOverflowDropNewest(t *testing.T) {
+	eventBus, _ := NewEventBus(nil, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		OverflowPolicy:   OverflowDropNewest,
+	})
+
+	assert.NoError(t, eventBus.Publish("first", nil))
+	newest := eventBus.PublishAsync("second", nil)
+
+	assert.ErrorIs(t, newest.Err(), ErrEventDropped, "O evento mais novo deve ser descartado")
+	assert.ErrorIs(t, eventBus.Publish("third", nil), ErrEventDropped, "Publish deve informar o descarte")
+	assert.Len(t, eventBus.requestQueue, 1)
+	assert.Equal(t, "first", (<-eventBus.requestQueue).Name)
+}
+
+func TestOverflowSpillRequiresDirectory(t *testing.T) {
+	_, err := NewEventBus(nil, nil, EventBusConfig{OverflowPolicy: OverflowSpill})
+	assert.EqualError(t, err, "spill directory is required for overflow policy spill")
+}
+
+func TestOverflowSpillDrainsToBroker(t *testing.T) {
+	broker := &CountingEventBroker{}
+	eventBus, err := NewEventBus(broker, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		BatchSize:        1,
+		FlushInterval:    10 * time.Millisecond,
+		OverflowPolicy:   OverflowSpill,
+		SpillDir:         t.TempDir(),
+	})
+	assert.NoError(t, err)
+
+	assert.NoError(t, eventBus.Publish("first", nil))
+	spilled := eventBus.PublishAsync("second", map[string]interface{}{"id": "42"})
+	assert.Equal(t, 1, eventBus.spill.Len(), "O evento excedente deve ser gravado em disco")
+
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, spilled.Wait(ctx))
+	assert.Equal(t, 0, eventBus.spill.Len())
+}
+
+func TestOverflowSpillSkipsCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "00000000000000000000.event"), []byte("garbage"), 0o644))
+	broker := &CountingEventBroker{}
+	eventBus, err := NewEventBus(broker, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		BatchSize:        1,
+		FlushInterval:    10 * time.Millisecond,
+		OverflowPolicy:   OverflowSpill,
+		SpillDir:         dir,
+	})
+	assert.NoError(t, err)
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+
+	delivered := eventBus.PublishAsync("order_created", nil)
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, delivered.Wait(ctx), "Um arquivo corrompido não deve travar o spill")
+	assert.Equal(t, 0, eventBus.spill.Len())
+	select {
+	case info := <-failures:
+		assert.Equal(t, StageSpill, info.Stage)
+	case <-time.After(time.Second):
+		t.Fatal("O arquivo corrompido deveria ser reportado")
+	}
+}
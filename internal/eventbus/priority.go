@@ -0,0 +1,174 @@
+package eventbus
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Priority int
+
+const (
+	PriorityLow Priority = iota + 1
+	PriorityNormal
+	PriorityHigh
+)
+
+var priorities = []Priority{PriorityHigh, PriorityNormal, PriorityLow}
+
+func (p Priority) String() string {
+	switch p {
+	case PriorityLow:
+		return "low"
+	case PriorityNormal:
+		return "normal"
+	case PriorityHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Priority(%d)", int(p))
+	}
+}
+
+func (p Priority) valid() bool {
+	return p >= PriorityLow && p <= PriorityHigh
+}
+
+func defaultPriorityWeights() map[Priority]int {
+	return map[Priority]int{
+		PriorityHigh:   6,
+		PriorityNormal: 3,
+		PriorityLow:    1,
+	}
+}
+
+func WithPriority(priority Priority) PublishOption {
+	return func(ep *EventPayload) {
+		ep.Priority = priority
+	}
+}
+
+type laneScheduler struct {
+	weights map[Priority]int
+	current map[Priority]int
+}
+
+func newLaneScheduler(weights map[Priority]int) *laneScheduler {
+	return &laneScheduler{
+		weights: weights,
+		current: make(map[Priority]int, len(weights)),
+	}
+}
+
+func (s *laneScheduler) pick(ready []Priority) Priority {
+	total := 0
+	best := ready[0]
+	for i, priority := range ready {
+		s.current[priority] += s.weights[priority]
+		total += s.weights[priority]
+		if i == 0 || s.current[priority] > s.current[best] {
+			best = priority
+		}
+	}
+	s.current[best] -= total
+	return best
+}
+
+func newLanes(size int) map[Priority]chan *EventPayload {
+	lanes := make(map[Priority]chan *EventPayload, len(priorities))
+	for _, priority := range priorities {
+		lanes[priority] = make(chan *EventPayload, size)
+	}
+	return lanes
+}
+
+func (eb *EventBus) resolvePriority(eventPayload *EventPayload) Priority {
+	if eventPayload.Priority.valid() {
+		return eventPayload.Priority
+	}
+	if priority, ok := eb.config.Priorities[eventPayload.Name]; ok {
+		return priority
+	}
+	return PriorityNormal
+}
+
+func (eb *EventBus) requestLane(eventPayload *EventPayload) chan *EventPayload {
+	eventPayload.Priority = eb.resolvePriority(eventPayload)
+	return eb.requestLanes[eventPayload.Priority]
+}
+
+func (eb *EventBus) pushRequest(eventPayload *EventPayload) {
+	eb.requestLane(eventPayload) <- eventPayload
+	eb.wake()
+}
+
+func (eb *EventBus) pushResponse(eventPayload *EventPayload) {
+	eventPayload.Priority = eb.resolvePriority(eventPayload)
+	eb.responseLanes[eventPayload.Priority] <- eventPayload
+}
+
+func (eb *EventBus) wake() {
+	select {
+	case eb.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func readyLanes(lanes map[Priority]chan *EventPayload) []Priority {
+	var ready []Priority
+	for _, priority := range priorities {
+		if len(lanes[priority]) > 0 {
+			ready = append(ready, priority)
+		}
+	}
+	return ready
+}
+
+func (eb *EventBus) dispatchRequest() bool {
+	ready := readyLanes(eb.requestLanes)
+	if len(ready) == 0 {
+		return false
+	}
+	select {
+	case eventPayload := <-eb.requestLanes[eb.requestScheduler.pick(ready)]:
+		eb.batch = append(eb.batch, eventPayload)
+		if len(eb.batch) >= eb.config.BatchSize {
+			eb.publishBatch()
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+func (eb *EventBus) dispatchResponse() bool {
+	ready := readyLanes(eb.responseLanes)
+	if len(ready) == 0 {
+		return false
+	}
+	lane := eb.responseScheduler.pick(ready)
+	select {
+	case eventPayload := <-eb.responseLanes[lane]:
+		if priority := eb.resolvePriority(eventPayload); priority != lane {
+			eventPayload.Priority = priority
+			select {
+			case eb.responseLanes[priority] <- eventPayload:
+				return true
+			default:
+			}
+		}
+		eb.processEnvelope(eventPayload)
+		return true
+	default:
+		return false
+	}
+}
+
+func (eb *EventBus) recordQueueSizes(ctx context.Context) {
+	for _, priority := range priorities {
+		eb.queueSize.Record(ctx, int64(len(eb.requestLanes[priority])), eb.metricAttrs(attribute.String("queue", "request"), attribute.String("priority", priority.String())))
+		eb.queueSize.Record(ctx, int64(len(eb.responseLanes[priority])), eb.metricAttrs(attribute.String("queue", "response"), attribute.String("priority", priority.String())))
+	}
+	eb.queueSize.Record(ctx, int64(len(eb.errorCallback)), eb.metricAttrs(attribute.String("queue", "error")))
+	eb.queueSize.Record(ctx, int64(eb.scheduler.Len()), eb.metricAttrs(attribute.String("queue", "scheduled")))
+}
@@ -0,0 +1,74 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaneSchedulerIsWeighted(t *testing.T) {
+	scheduler := newLaneScheduler(defaultPriorityWeights())
+	counts := make(map[Priority]int)
+	for i := 0; i < 10; i++ {
+		counts[scheduler.pick([]Priority{PriorityHigh, PriorityNormal, PriorityLow})]++
+	}
+
+	assert.Equal(t, 6, counts[PriorityHigh])
+	assert.Equal(t, 3, counts[PriorityNormal])
+	assert.Equal(t, 1, counts[PriorityLow], "Lanes de baixa prioridade não devem sofrer starvation")
+}
+
+func TestResolvePriority(t *testing.T) {
+	eventBus, err := New(WithEventPriority("payment_captured", PriorityHigh))
+	assert.NoError(t, err)
+
+	assert.Equal(t, PriorityHigh, eventBus.resolvePriority(&EventPayload{Name: "payment_captured"}), "A prioridade configurada por evento deve ser usada")
+	assert.Equal(t, PriorityLow, eventBus.resolvePriority(&EventPayload{Name: "payment_captured", Priority: PriorityLow}), "A prioridade da publicação tem precedência")
+	assert.Equal(t, PriorityNormal, eventBus.resolvePriority(&EventPayload{Name: "page_viewed"}))
+}
+
+func TestPublishUsesPriorityLanes(t *testing.T) {
+	eventBus, _ := New(WithRequestQueueSize(1))
+
+	assert.NoError(t, eventBus.Publish("page_viewed", nil, WithPriority(PriorityLow)))
+	assert.NoError(t, eventBus.Publish("payment_captured", nil, WithPriority(PriorityHigh)))
+	assert.NoError(t, eventBus.Publish("order_created", nil))
+	assert.ErrorIs(t, eventBus.Publish("page_viewed", nil, WithPriority(PriorityLow)), ErrQueueFull, "Cada lane deve ter sua própria capacidade")
+
+	assert.Len(t, eventBus.requestLanes[PriorityLow], 1)
+	assert.Len(t, eventBus.requestLanes[PriorityHigh], 1)
+	assert.Len(t, eventBus.requestQueue, 1, "A requestQueue corresponde à lane normal")
+}
+
+func TestDispatchRequestPrefersHighPriority(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(100))
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, eventBus.Publish("page_viewed", nil, WithPriority(PriorityLow)))
+	}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, eventBus.Publish("payment_captured", nil, WithPriority(PriorityHigh)))
+	}
+
+	for eventBus.dispatchRequest() {
+	}
+
+	assert.Len(t, eventBus.batch, 10)
+	highInFirstHalf := 0
+	for _, eventPayload := range eventBus.batch[:5] {
+		if eventPayload.Priority == PriorityHigh {
+			highInFirstHalf++
+		}
+	}
+	assert.GreaterOrEqual(t, highInFirstHalf, 4, "Eventos de alta prioridade devem ser despachados primeiro")
+}
+
+func TestPriorityOptionsValidation(t *testing.T) {
+	_, err := New(WithEventPriority("payment_captured", Priority(9)))
+	assert.EqualError(t, err, "unknown priority Priority(9) for event payment_captured")
+
+	_, err = New(WithPriorityWeights(map[Priority]int{PriorityHigh: 1, PriorityNormal: 1}))
+	assert.EqualError(t, err, "weight for priority low must be positive, got 0")
+
+	_, err = NewEventBus(nil, nil, EventBusConfig{Priorities: map[string]Priority{"page_viewed": 0}})
+	assert.EqualError(t, err, "unknown priority Priority(0) for event page_viewed")
+}
@@ -0,0 +1,51 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var ErrBusStopped = errors.New("event bus stopped")
+
+type PublishFuture struct {
+	once *sync.Once
+	done chan struct{}
+	err  error
+}
+
+func newPublishFuture() *PublishFuture {
+	return &PublishFuture{
+		once: &sync.Once{},
+		done: make(chan struct{}),
+	}
+}
+
+func (f *PublishFuture) resolve(err error) {
+	f.once.Do(func() {
+		f.err = err
+		close(f.done)
+	})
+}
+
+func (f *PublishFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+func (f *PublishFuture) Err() error {
+	select {
+	case <-f.done:
+		return f.err
+	default:
+		return nil
+	}
+}
+
+func (f *PublishFuture) Wait(ctx context.Context) error {
+	select {
+	case <-f.done:
+		return f.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishFutureResolve(t *testing.T) {
+	future := newPublishFuture()
+	assert.NoError(t, future.Err(), "Err deve ser nil antes da resolução")
+
+	future.resolve(errors.New("nack"))
+	future.resolve(nil)
+
+	select {
+	case <-future.Done():
+	default:
+		t.Fatal("Done deve estar fechado após resolve")
+	}
+	assert.EqualError(t, future.Err(), "nack", "Apenas a primeira resolução deve ser considerada")
+	assert.EqualError(t, future.Wait(context.Background()), "nack")
+}
+
+func TestPublishFutureWaitContext(t *testing.T) {
+	future := newPublishFuture()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := future.Wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestPublishAfterStopResolvesFuture(t *testing.T) {
+	eventBus, _ := New()
+	eventBus.Start()
+	eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, eventBus.PublishAsync("order_created", 1).Wait(ctx), ErrBusStopped, "Publicações após o Stop devem ser rejeitadas")
+	assert.ErrorIs(t, eventBus.Publish("order_created", 1), ErrBusStopped)
+}
+
+func TestStopWithoutStartAbandonsPending(t *testing.T) {
+	eventBus, _ := New()
+	future := eventBus.PublishAsync("order_created", 1)
+	eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, future.Wait(ctx), ErrBusStopped, "Eventos pendentes devem ser resolvidos no Stop")
+}
+
+func TestStopResolvesSpilledFutures(t *testing.T) {
+	eventBus, err := NewEventBus(nil, nil, EventBusConfig{
+		RequestQueueSize: 1,
+		OverflowPolicy:   OverflowSpill,
+		SpillDir:         t.TempDir(),
+	})
+	assert.NoError(t, err)
+	eventBus.PublishAsync("order_created", 1)
+	spilled := eventBus.PublishAsync("order_created", 2)
+	assert.Equal(t, 1, eventBus.spill.Len())
+	eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.ErrorIs(t, spilled.Wait(ctx), ErrBusStopped, "Eventos derramados em disco devem ser resolvidos no Stop")
+	assert.Equal(t, 1, eventBus.spill.Len(), "O evento derramado deve continuar em disco para o próximo start")
+}
+
+func TestPublishRacingStopAlwaysResolves(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		eventBus, _ := New(WithFlushInterval(time.Hour), WithBatchSize(1000))
+		eventBus.Start()
+		futures := make(chan *PublishFuture, 100)
+		go func() {
+			for j := 0; j < 100; j++ {
+				futures <- eventBus.PublishAsync("order_created", j)
+			}
+			close(futures)
+		}()
+		eventBus.Stop()
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		for future := range futures {
+			err := future.Wait(ctx)
+			assert.NotErrorIs(t, err, context.DeadlineExceeded, "Nenhuma future deve ficar pendente após o Stop")
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,159 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+var ErrRateLimited = errors.New("rate limited")
+
+type RateLimitMode int
+
+const (
+	RateLimitWait RateLimitMode = iota
+	RateLimitReject
+)
+
+func (m RateLimitMode) String() string {
+	switch m {
+	case RateLimitWait:
+		return "wait"
+	case RateLimitReject:
+		return "reject"
+	default:
+		return fmt.Sprintf("RateLimitMode(%d)", int(m))
+	}
+}
+
+type RateLimit struct {
+	Rate  float64
+	Burst int
+	Mode  RateLimitMode
+}
+
+func (l RateLimit) validate(name string) error {
+	if l.Rate <= 0 {
+		return fmt.Errorf("rate limit for event %s must be positive, got %v", name, l.Rate)
+	}
+	if l.Burst < 1 {
+		return fmt.Errorf("burst for event %s must be positive, got %d", name, l.Burst)
+	}
+	if l.Mode < RateLimitWait || l.Mode > RateLimitReject {
+		return fmt.Errorf("unknown rate limit mode %s for event %s", l.Mode, name)
+	}
+	return nil
+}
+
+type tokenBucket struct {
+	mutex  *sync.Mutex
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+func newTokenBucket(limit RateLimit) *tokenBucket {
+	return &tokenBucket{
+		mutex:  &sync.Mutex{},
+		limit:  limit,
+		tokens: float64(limit.Burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+func (b *tokenBucket) refill(now time.Time) {
+	elapsed := now.Sub(b.last).Seconds()
+	b.last = now
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+elapsed*b.limit.Rate)
+}
+
+func (b *tokenBucket) allow() bool {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(b.now())
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+func (b *tokenBucket) reserve() time.Duration {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.refill(b.now())
+	b.tokens--
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.limit.Rate * float64(time.Second))
+}
+
+func (b *tokenBucket) cancel() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.tokens = math.Min(float64(b.limit.Burst), b.tokens+1)
+}
+
+func (b *tokenBucket) take(ctx context.Context) (bool, error) {
+	if b.limit.Mode == RateLimitReject {
+		if !b.allow() {
+			return false, ErrRateLimited
+		}
+		return false, nil
+	}
+	delay := b.reserve()
+	if delay == 0 {
+		return false, nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true, nil
+	case <-ctx.Done():
+		b.cancel()
+		return true, errors.Join(ErrRateLimited, ctx.Err())
+	}
+}
+
+func newTokenBuckets(limits map[string]RateLimit) map[string]*tokenBucket {
+	buckets := make(map[string]*tokenBucket, len(limits))
+	for name, limit := range limits {
+		buckets[name] = newTokenBucket(limit)
+	}
+	return buckets
+}
+
+func (eb *EventBus) throttle(ctx context.Context, buckets map[string]*tokenBucket, stage string, envelope *EventPayload) error {
+	bucket, ok := buckets[envelope.Name]
+	if !ok {
+		return nil
+	}
+	waited, err := bucket.take(ctx)
+	outcome := ""
+	switch {
+	case err != nil:
+		outcome = "rejected"
+	case waited:
+		outcome = "waited"
+	}
+	if outcome != "" {
+		eb.throttledCounter.Add(ctx, 1, eb.metricAttrs(
+			attribute.String("event_name", envelope.Name),
+			attribute.String("stage", stage),
+			attribute.String("outcome", outcome),
+		))
+	}
+	if err != nil {
+		return fmt.Errorf("event %s: %w", envelope.Name, err)
+	}
+	return nil
+}
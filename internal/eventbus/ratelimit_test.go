@@ -0,0 +1,186 @@
+package eventbus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestTokenBucketRefills(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 2, Mode: RateLimitReject})
+	now := time.Now()
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+
+	assert.True(t, bucket.allow())
+	assert.True(t, bucket.allow())
+	assert.False(t, bucket.allow(), "O burst deve limitar as chamadas imediatas")
+
+	now = now.Add(100 * time.Millisecond)
+	assert.True(t, bucket.allow())
+	assert.False(t, bucket.allow())
+
+	now = now.Add(time.Hour)
+	assert.True(t, bucket.allow())
+	assert.True(t, bucket.allow())
+	assert.False(t, bucket.allow(), "Os tokens acumulados não devem passar do burst")
+}
+
+func TestTokenBucketReserveReturnsDelay(t *testing.T) {
+	bucket := newTokenBucket(RateLimit{Rate: 10, Burst: 1})
+	now := time.Now()
+	bucket.last = now
+	bucket.now = func() time.Time { return now }
+
+	assert.Zero(t, bucket.reserve())
+	assert.Equal(t, 100*time.Millisecond, bucket.reserve())
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve())
+	bucket.cancel()
+	assert.Equal(t, 200*time.Millisecond, bucket.reserve(), "Reservas canceladas devem devolver o token")
+}
+
+func TestPublishRateLimitRejects(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, _ := New(
+		WithPublishRateLimit("page_viewed", RateLimit{Rate: 1, Burst: 2, Mode: RateLimitReject}),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+
+	assert.NoError(t, eventBus.Publish("page_viewed", nil))
+	assert.NoError(t, eventBus.Publish("page_viewed", nil))
+	err := eventBus.Publish("page_viewed", nil)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.EqualError(t, err, "event page_viewed: rate limited")
+	assert.NoError(t, eventBus.Publish("order_created", nil), "Eventos sem limite não devem ser afetados")
+
+	points := collectSums(t, reader, "eventbus.throttled")
+	if assert.Len(t, points, 1) {
+		outcome, _ := points[0].Attributes.Value("outcome")
+		stage, _ := points[0].Attributes.Value("stage")
+		assert.Equal(t, "rejected", outcome.AsString())
+		assert.Equal(t, "publish", stage.AsString())
+	}
+}
+
+func TestPublishRateLimitWaits(t *testing.T) {
+	eventBus, _ := New(WithPublishRateLimit("page_viewed", RateLimit{Rate: 20, Burst: 1}))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, eventBus.Publish("page_viewed", nil))
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond, "Publicações devem aguardar tokens no modo wait")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	eventBus.publishLimits["page_viewed"].limit.Rate = 0.1
+	err := eventBus.PublishContext(ctx, "page_viewed", nil)
+	assert.ErrorIs(t, err, ErrRateLimited)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestHandlerRateLimitThrottlesDispatch(t *testing.T) {
+	eventBus, _ := New(WithHandlerRateLimit("call_api", RateLimit{Rate: 1, Burst: 1, Mode: RateLimitReject}))
+	var calls int32
+	errs := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { errs <- info })
+	eventBus.Register([]*Event{{
+		Name: "call_api",
+		Handler: func(payload interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, nil
+		},
+	}})
+
+	eventBus.ProcessEvent("call_api", 1)
+	eventBus.ProcessEvent("call_api", 2)
+	select {
+	case info := <-errs:
+		assert.Equal(t, StageDispatch, info.Stage)
+		assert.ErrorIs(t, info.Err, ErrRateLimited)
+	case <-time.After(time.Second):
+		t.Fatal("Eventos acima do limite deveriam ser rejeitados")
+	}
+	assert.Eventually(t, func() bool { return atomic.LoadInt32(&calls) == 1 }, time.Second, 10*time.Millisecond)
+}
+
+func TestHandlerRateLimitWaitsOutsideWorkerSlot(t *testing.T) {
+	eventBus, _ := New(
+		WithWorkerPoolSize(1),
+		WithHandlerRateLimit("call_api", RateLimit{Rate: 10, Burst: 1, Mode: RateLimitWait}),
+		WithHandlerTimeout("call_api", 30*time.Millisecond),
+	)
+	failures := make(chan ErrorInfo, 2)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	calls := make(chan interface{}, 2)
+	fast := make(chan struct{}, 1)
+	eventBus.Register([]*Event{
+		{Name: "call_api", Handler: func(payload interface{}) (interface{}, error) { calls <- payload; return nil, nil }},
+		{Name: "send_email", Handler: func(payload interface{}) (interface{}, error) { fast <- struct{}{}; return nil, nil }},
+	})
+
+	eventBus.ProcessEvent("call_api", 1)
+	eventBus.ProcessEvent("call_api", 2)
+	eventBus.ProcessEvent("send_email", nil)
+	select {
+	case <-fast:
+	case <-time.After(50 * time.Millisecond):
+		t.Fatal("Handlers aguardando o rate limit não devem ocupar o worker pool")
+	}
+
+	for i := 0; i < 2; i++ {
+		select {
+		case <-calls:
+		case <-time.After(time.Second):
+			t.Fatal("O handler limitado deveria executar após a espera")
+		}
+	}
+	select {
+	case info := <-failures:
+		t.Fatalf("A espera do rate limit não deve consumir o timeout do handler: %v", info.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestHandlerRateLimitWaitIsBounded(t *testing.T) {
+	eventBus, _ := New(
+		WithPendingQueueSize(1),
+		WithHandlerRateLimit("call_api", RateLimit{Rate: 0.01, Burst: 1, Mode: RateLimitWait}),
+		WithTimeout(30*time.Millisecond),
+	)
+	failures := make(chan ErrorInfo, 2)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	var calls int32
+	eventBus.Register([]*Event{{Name: "call_api", Handler: func(payload interface{}) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		return nil, nil
+	}}})
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		eventBus.ProcessEvent("call_api", i)
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 30*time.Millisecond, "O despacho deve esperar quando a fila de pendentes estiver cheia")
+	for i := 0; i < 2; i++ {
+		select {
+		case info := <-failures:
+			assert.Equal(t, StageDispatch, info.Stage)
+			assert.ErrorIs(t, info.Err, ErrRateLimited)
+			assert.ErrorIs(t, info.Err, context.DeadlineExceeded, "A espera do rate limit deve respeitar o Timeout")
+		case <-time.After(time.Second):
+			t.Fatal("Handlers aguardando o rate limit deveriam desistir após o timeout")
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestRateLimitValidates(t *testing.T) {
+	_, err := New(WithPublishRateLimit("page_viewed", RateLimit{Rate: 0, Burst: 1}))
+	assert.EqualError(t, err, "rate limit for event page_viewed must be positive, got 0")
+	_, err = New(WithHandlerRateLimit("call_api", RateLimit{Rate: 1}))
+	assert.EqualError(t, err, "burst for event call_api must be positive, got 0")
+}
@@ -0,0 +1,52 @@
+package eventbus
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type envelopeRecord struct {
+	ID            string            `json:"id"`
+	CorrelationID string            `json:"correlation_id"`
+	Name          string            `json:"name"`
+	Key           string            `json:"key,omitempty"`
+	Priority      Priority          `json:"priority,omitempty"`
+	Headers       map[string]string `json:"headers,omitempty"`
+	Payload       []byte            `json:"payload"`
+}
+
+func EncodeEnvelope(codec Codec, envelope *EventPayload) ([]byte, error) {
+	payload, err := codec.Marshal(envelope.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payload: %w", err)
+	}
+	return json.Marshal(envelopeRecord{
+		ID:            envelope.ID,
+		CorrelationID: envelope.CorrelationID,
+		Name:          envelope.Name,
+		Key:           envelope.Key,
+		Priority:      envelope.Priority,
+		Headers:       envelope.Headers,
+		Payload:       payload,
+	})
+}
+
+func DecodeEnvelope(codec Codec, data []byte) (*EventPayload, error) {
+	var record envelopeRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to decode envelope: %w", err)
+	}
+	var payload interface{}
+	if err := codec.Unmarshal(record.Payload, &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode payload: %w", err)
+	}
+	return &EventPayload{
+		ID:            record.ID,
+		CorrelationID: record.CorrelationID,
+		Name:          record.Name,
+		Key:           record.Key,
+		Priority:      record.Priority,
+		Headers:       record.Headers,
+		Payload:       payload,
+	}, nil
+}
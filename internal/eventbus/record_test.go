@@ -0,0 +1,31 @@
+package eventbus
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvelopeRecordRoundTrip(t *testing.T) {
+	envelope := &EventPayload{
+		ID:            "id",
+		CorrelationID: "corr",
+		Name:          "order_created",
+		Key:           "order-1",
+		Priority:      PriorityHigh,
+		Headers:       map[string]string{"tenant": "acme"},
+		Payload:       map[string]interface{}{"amount": 10.5},
+	}
+
+	data, err := EncodeEnvelope(JSONCodec{}, envelope)
+	assert.NoError(t, err)
+
+	decoded, err := DecodeEnvelope(JSONCodec{}, data)
+	assert.NoError(t, err)
+	assert.Equal(t, envelope, decoded)
+}
+
+func TestDecodeEnvelopeInvalidData(t *testing.T) {
+	_, err := DecodeEnvelope(JSONCodec{}, []byte("not json"))
+	assert.ErrorContains(t, err, "failed to decode envelope")
+}
@@ -0,0 +1,171 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	HeaderReplay    = "replay"
+	replayBatchSize = 100
+)
+
+type ReplayRecord struct {
+	Position   int64
+	RecordedAt time.Time
+	Envelope   *EventPayload
+}
+
+type ReplaySource interface {
+	ReadRecords(ctx context.Context, from int64, limit int) ([]*ReplayRecord, error)
+}
+
+type ReplayOptions struct {
+	From         time.Time
+	To           time.Time
+	FromPosition int64
+	ToPosition   int64
+	Names        []string
+	Handlers     []*Event
+	Rate         float64
+	DryRun       bool
+}
+
+type ReplayResult struct {
+	Scanned  int
+	Replayed int
+	Handled  int
+	Skipped  int
+}
+
+func (ep *EventPayload) replayed() bool {
+	return ep.Headers[HeaderReplay] != ""
+}
+
+func (o ReplayOptions) matches(record *ReplayRecord, names map[string]bool) bool {
+	if !o.From.IsZero() && record.RecordedAt.Before(o.From) {
+		return false
+	}
+	if !o.To.IsZero() && !record.RecordedAt.Before(o.To) {
+		return false
+	}
+	return len(names) == 0 || names[record.Envelope.Name]
+}
+
+func (o ReplayOptions) selects(event *Event) bool {
+	if len(o.Handlers) == 0 {
+		return true
+	}
+	for _, handler := range o.Handlers {
+		if handler == event {
+			return true
+		}
+	}
+	return false
+}
+
+func (eb *EventBus) Replay(ctx context.Context, source ReplaySource, opts ReplayOptions) (ReplayResult, error) {
+	var result ReplayResult
+	if source == nil {
+		return result, errors.New("replay source cannot be nil")
+	}
+	if opts.Rate < 0 {
+		return result, errors.New("replay rate cannot be negative")
+	}
+
+	ctx, span := eb.tracer.Start(ctx, "Replay")
+	span.SetAttributes(attribute.Bool("dry_run", opts.DryRun))
+	defer span.End()
+
+	names := make(map[string]bool, len(opts.Names))
+	for _, name := range opts.Names {
+		names[name] = true
+	}
+	var pace <-chan time.Time
+	if opts.Rate > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / opts.Rate))
+		defer ticker.Stop()
+		pace = ticker.C
+	}
+
+	position := opts.FromPosition
+	for {
+		records, err := source.ReadRecords(ctx, position, replayBatchSize)
+		if err != nil {
+			return result, err
+		}
+		for _, record := range records {
+			if opts.ToPosition > 0 && record.Position > opts.ToPosition {
+				return eb.finishReplay(ctx, result, nil)
+			}
+			position = record.Position + 1
+			result.Scanned++
+			if !opts.matches(record, names) {
+				continue
+			}
+			if pace != nil {
+				select {
+				case <-pace:
+				case <-ctx.Done():
+					return eb.finishReplay(ctx, result, ctx.Err())
+				}
+			}
+			handled, skipped := eb.replayEnvelope(ctx, record.Envelope, opts)
+			result.Replayed++
+			result.Handled += handled
+			result.Skipped += skipped
+		}
+		if len(records) < replayBatchSize {
+			return eb.finishReplay(ctx, result, nil)
+		}
+		if err := ctx.Err(); err != nil {
+			return eb.finishReplay(ctx, result, err)
+		}
+	}
+}
+
+func (eb *EventBus) finishReplay(ctx context.Context, result ReplayResult, err error) (ReplayResult, error) {
+	eb.logger.InfoContext(ctx, "replay finished",
+		slog.Int("scanned", result.Scanned),
+		slog.Int("replayed", result.Replayed),
+		slog.Int("handled", result.Handled),
+		slog.Int("skipped", result.Skipped),
+	)
+	return result, err
+}
+
+func (eb *EventBus) replayEnvelope(ctx context.Context, original *EventPayload, opts ReplayOptions) (int, int) {
+	envelope := original.clone()
+	WithHeader(HeaderReplay, "true")(envelope)
+
+	eb.mutex.Lock()
+	events, err := eb.eventRegistry.Get(envelope.Name)
+	eb.mutex.Unlock()
+	if err != nil {
+		return 0, 0
+	}
+	handled, skipped := 0, 0
+	for _, event := range events {
+		if !opts.selects(event) {
+			continue
+		}
+		if opts.DryRun && event.SideEffects {
+			eb.logger.InfoContext(ctx, "replay skipped side-effecting handler", eb.logAttrs(ctx, envelope)...)
+			skipped++
+			continue
+		}
+		eb.pendingHandlers <- struct{}{}
+		release, ok := eb.acquireWorker(ctx, envelope, event)
+		if !ok {
+			continue
+		}
+		eb.invokeHandler(ctx, envelope, event)
+		release()
+		handled++
+	}
+	return handled, skipped
+}
@@ -0,0 +1,157 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type sliceReplaySource []*ReplayRecord
+
+func (s sliceReplaySource) ReadRecords(ctx context.Context, from int64, limit int) ([]*ReplayRecord, error) {
+	var records []*ReplayRecord
+	for _, record := range s {
+		if record.Position >= from && len(records) < limit {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+func newReplaySource(start time.Time, names ...string) sliceReplaySource {
+	source := make(sliceReplaySource, len(names))
+	for i, name := range names {
+		source[i] = &ReplayRecord{
+			Position:   int64(i + 1),
+			RecordedAt: start.Add(time.Duration(i) * time.Hour),
+			Envelope:   NewEnvelope(name, i+1),
+		}
+	}
+	return source
+}
+
+type replayRecorder struct {
+	mutex    sync.Mutex
+	payloads []interface{}
+}
+
+func (r *replayRecorder) handler(payload interface{}) (interface{}, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.payloads = append(r.payloads, payload)
+	return nil, nil
+}
+
+func (r *replayRecorder) get() []interface{} {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return append([]interface{}(nil), r.payloads...)
+}
+
+func TestReplayFiltersByTimeNameAndPosition(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := newReplaySource(start, "order_created", "order_paid", "order_created", "order_created", "order_created")
+	eventBus, _ := New()
+	recorder := &replayRecorder{}
+	eventBus.Register([]*Event{{Name: "order_created", Handler: recorder.handler}})
+
+	result, err := eventBus.Replay(context.Background(), source, ReplayOptions{
+		From:       start.Add(time.Hour),
+		To:         start.Add(4 * time.Hour),
+		Names:      []string{"order_created"},
+		ToPosition: 4,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{3, 4}, recorder.get())
+	assert.Equal(t, ReplayResult{Scanned: 4, Replayed: 2, Handled: 2}, result)
+}
+
+func TestReplayRunsOnlySelectedHandlers(t *testing.T) {
+	source := newReplaySource(time.Now(), "order_created")
+	eventBus, _ := New()
+	fixed, other := &replayRecorder{}, &replayRecorder{}
+	fixedHandler := &Event{Name: "order_created", Handler: fixed.handler}
+	eventBus.Register([]*Event{fixedHandler, {Name: "order_created", Handler: other.handler}})
+
+	_, err := eventBus.Replay(context.Background(), source, ReplayOptions{Handlers: []*Event{fixedHandler}})
+	assert.NoError(t, err)
+	assert.Len(t, fixed.get(), 1)
+	assert.Empty(t, other.get(), "Handlers não selecionados não devem ser executados")
+}
+
+func TestReplayDryRunSkipsSideEffects(t *testing.T) {
+	source := newReplaySource(time.Now(), "order_created", "order_created")
+	eventBus, _ := New()
+	readModel, email := &replayRecorder{}, &replayRecorder{}
+	eventBus.Register([]*Event{
+		{Name: "order_created", Handler: readModel.handler},
+		{Name: "order_created", Handler: email.handler, SideEffects: true},
+	})
+
+	result, err := eventBus.Replay(context.Background(), source, ReplayOptions{DryRun: true})
+	assert.NoError(t, err)
+	assert.Len(t, readModel.get(), 2)
+	assert.Empty(t, email.get(), "Handlers com efeitos colaterais não devem rodar em dry-run")
+	assert.Equal(t, ReplayResult{Scanned: 2, Replayed: 2, Handled: 2, Skipped: 2}, result)
+}
+
+func TestReplayDoesNotTriggerFollowUpsOrDedup(t *testing.T) {
+	source := newReplaySource(time.Now(), "order_created")
+	eventBus, _ := New(WithDedupStore(NewMemoryDedupStore(10, time.Hour)))
+	recorder, next := &replayRecorder{}, &replayRecorder{}
+	eventBus.Register([]*Event{
+		{Name: "order_created", Handler: recorder.handler, Next: &Event{Name: "send_email"}},
+		{Name: "send_email", Handler: next.handler},
+	})
+	eventBus.processEnvelope(source[0].Envelope)
+	assert.Eventually(t, func() bool { return len(eventBus.requestLanes[PriorityNormal]) == 1 }, time.Second, 10*time.Millisecond)
+
+	_, err := eventBus.Replay(context.Background(), source, ReplayOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, recorder.get(), 2, "O replay deve ignorar o dedup store")
+	assert.Equal(t, 1, len(eventBus.requestLanes[PriorityNormal]), "O replay não deve publicar eventos Next")
+}
+
+func TestReplayLeavesBreakerAndDedupUntouched(t *testing.T) {
+	source := newReplaySource(time.Now(), "charge_card", "charge_card")
+	store := NewMemoryDedupStore(10, time.Hour)
+	eventBus, _ := New(
+		WithDedupStore(store),
+		WithCircuitBreaker("charge_card", CircuitBreakerConfig{FailureRatio: 1, MinRequests: 1, Cooldown: time.Minute}),
+		WithHandlerRateLimit("charge_card", RateLimit{Rate: 0.001, Burst: 1, Mode: RateLimitReject}),
+	)
+	eventBus.Register([]*Event{{Name: "charge_card", Handler: func(payload interface{}) (interface{}, error) {
+		return nil, errors.New("gateway timeout")
+	}}})
+	for _, record := range source {
+		_, _ = store.Add(dedupKey(record.Envelope))
+	}
+
+	result, err := eventBus.Replay(context.Background(), source, ReplayOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.Handled, "O segundo evento deve ser limitado pelo rate limit")
+	state, _ := eventBus.BreakerState("charge_card")
+	assert.Equal(t, BreakerClosed, state, "Falhas no replay não devem alterar o breaker")
+	for _, record := range source {
+		added, _ := store.Add(dedupKey(record.Envelope))
+		assert.False(t, added, "O replay não deve remover chaves de dedup dos eventos originais")
+	}
+}
+
+func TestReplayRateLimit(t *testing.T) {
+	source := newReplaySource(time.Now(), "order_created", "order_created", "order_created")
+	eventBus, _ := New()
+	eventBus.Register([]*Event{{Name: "order_created", Handler: (&replayRecorder{}).handler}})
+
+	start := time.Now()
+	_, err := eventBus.Replay(context.Background(), source, ReplayOptions{Rate: 20})
+	assert.NoError(t, err)
+	assert.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond, "O replay deve respeitar a taxa configurada")
+
+	_, err = eventBus.Replay(context.Background(), source, ReplayOptions{Rate: -1})
+	assert.EqualError(t, err, "replay rate cannot be negative")
+}
@@ -0,0 +1,142 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+const (
+	HeaderReplyTo    = "reply_to"
+	HeaderReplyFrom  = "reply_from"
+	HeaderReplyError = "reply_error"
+)
+
+const replyInboxPrefix = "eventbus.reply."
+
+var ErrNoReply = errors.New("no reply received")
+
+type RemoteError struct {
+	Message string
+}
+
+func (e *RemoteError) Error() string {
+	return e.Message
+}
+
+type Reply struct {
+	Responder string
+	Payload   interface{}
+	Err       error
+}
+
+type replyRouter struct {
+	mutex   *sync.Mutex
+	inbox   string
+	pending map[string]chan *EventPayload
+}
+
+func newReplyRouter() *replyRouter {
+	return &replyRouter{
+		mutex:   &sync.Mutex{},
+		inbox:   replyInboxPrefix + newID(),
+		pending: make(map[string]chan *EventPayload),
+	}
+}
+
+func (r *replyRouter) register(correlationID string, size int) chan *EventPayload {
+	replies := make(chan *EventPayload, size)
+	r.mutex.Lock()
+	r.pending[correlationID] = replies
+	r.mutex.Unlock()
+	return replies
+}
+
+func (r *replyRouter) unregister(correlationID string) {
+	r.mutex.Lock()
+	delete(r.pending, correlationID)
+	r.mutex.Unlock()
+}
+
+func (r *replyRouter) deliver(envelope *EventPayload) {
+	r.mutex.Lock()
+	replies, ok := r.pending[envelope.CorrelationID]
+	r.mutex.Unlock()
+	if !ok {
+		return
+	}
+	select {
+	case replies <- envelope:
+	default:
+	}
+}
+
+func newReply(envelope *EventPayload) Reply {
+	reply := Reply{Responder: envelope.Headers[HeaderReplyFrom], Payload: envelope.Payload}
+	if message, ok := envelope.Headers[HeaderReplyError]; ok {
+		reply.Err = &RemoteError{Message: message}
+	}
+	return reply
+}
+
+func (eb *EventBus) Request(ctx context.Context, name string, payload interface{}, opts ...PublishOption) (interface{}, error) {
+	replies, err := eb.RequestAll(ctx, name, payload, 1, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return replies[0].Payload, replies[0].Err
+}
+
+func (eb *EventBus) RequestAll(ctx context.Context, name string, payload interface{}, expected int, opts ...PublishOption) ([]Reply, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, eb.config.Timeout)
+		defer cancel()
+	}
+
+	envelope := NewEnvelope(name, payload, append(opts, WithHeader(HeaderReplyTo, eb.replies.inbox))...)
+	size := expected
+	if size < 1 {
+		size = eb.config.ResponseQueueSize
+	}
+	incoming := eb.replies.register(envelope.CorrelationID, size)
+	defer eb.replies.unregister(envelope.CorrelationID)
+
+	if err := eb.publishEnvelope(ctx, envelope); err != nil {
+		return nil, err
+	}
+
+	var replies []Reply
+	for expected < 1 || len(replies) < expected {
+		select {
+		case reply := <-incoming:
+			replies = append(replies, newReply(reply))
+		case <-ctx.Done():
+			if len(replies) == 0 {
+				return nil, errors.Join(ErrNoReply, ctx.Err())
+			}
+			return replies, nil
+		}
+	}
+	return replies, nil
+}
+
+func (eb *EventBus) reply(ctx context.Context, request *EventPayload, output interface{}, err error) {
+	replyTo := request.Headers[HeaderReplyTo]
+	if replyTo == "" {
+		return
+	}
+	headers := map[string]string{HeaderReplyFrom: eb.name}
+	if err != nil {
+		headers[HeaderReplyError] = err.Error()
+	}
+	eb.pushRequest(&EventPayload{
+		ID:            newID(),
+		CorrelationID: request.CorrelationID,
+		Name:          replyTo,
+		Key:           request.Key,
+		Priority:      request.Priority,
+		Headers:       headers,
+		Payload:       output,
+	})
+}
@@ -0,0 +1,123 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestReturnsHandlerOutput(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	eventBus.Register([]*Event{{
+		Name: "get_price",
+		Handler: func(payload interface{}) (interface{}, error) {
+			return payload.(int) * 2, nil
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	reply, err := eventBus.Request(ctx, "get_price", 21)
+	assert.NoError(t, err)
+	assert.Equal(t, 42, reply)
+}
+
+func TestRequestReturnsRemoteError(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	eventBus.Register([]*Event{{
+		Name: "get_price",
+		Handler: func(payload interface{}) (interface{}, error) {
+			return nil, errors.New("product not found")
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	_, err := eventBus.Request(ctx, "get_price", 21)
+	var remote *RemoteError
+	if assert.ErrorAs(t, err, &remote) {
+		assert.Equal(t, "product not found", remote.Message)
+	}
+}
+
+func TestRequestTimesOutWithoutResponder(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := eventBus.Request(ctx, "get_price", 21)
+	assert.ErrorIs(t, err, ErrNoReply)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, eventBus.replies.pending, "Requisições expiradas devem ser descartadas")
+}
+
+func TestRequestAllGathersReplies(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	quote := func(price int) func(interface{}) (interface{}, error) {
+		return func(payload interface{}) (interface{}, error) {
+			return price, nil
+		}
+	}
+	eventBus.Register([]*Event{
+		{Name: "get_quote", Handler: quote(10)},
+		{Name: "get_quote", Handler: quote(12)},
+		{Name: "get_quote", Handler: quote(9)},
+	})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	replies, err := eventBus.RequestAll(ctx, "get_quote", nil, 3)
+	assert.NoError(t, err)
+
+	var prices []interface{}
+	for _, reply := range replies {
+		assert.NoError(t, reply.Err)
+		assert.Equal(t, defaultInstanceName, reply.Responder)
+		prices = append(prices, reply.Payload)
+	}
+	assert.ElementsMatch(t, []interface{}{10, 12, 9}, prices)
+}
+
+func TestRequestAllReturnsPartialRepliesOnTimeout(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	eventBus.Register([]*Event{{
+		Name: "get_quote",
+		Handler: func(payload interface{}) (interface{}, error) {
+			return 10, nil
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	replies, err := eventBus.RequestAll(ctx, "get_quote", nil, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, replies, 1) {
+		assert.Equal(t, 10, replies[0].Payload)
+	}
+}
+
+func TestForeignReplyInboxIsIgnored(t *testing.T) {
+	eventBus, _ := New()
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+
+	eventBus.ProcessEvent("eventbus.reply.another-bus", "pong")
+	select {
+	case info := <-failures:
+		t.Fatalf("Respostas de outros barramentos devem ser ignoradas, recebido %v", info.Err)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
@@ -0,0 +1,257 @@
+package eventbus
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+const scheduleFileSuffix = ".scheduled"
+
+type ScheduledEvent struct {
+	DueAt    time.Time
+	Envelope *EventPayload
+	retrying bool
+}
+
+type ScheduleStore interface {
+	Save(scheduled *ScheduledEvent) error
+	Delete(id string) error
+	Load() ([]*ScheduledEvent, error)
+}
+
+type scheduledRecord struct {
+	DueAt    time.Time       `json:"due_at"`
+	Envelope json.RawMessage `json:"envelope"`
+}
+
+type FileScheduleStore struct {
+	mutex *sync.Mutex
+	dir   string
+	codec Codec
+}
+
+func NewFileScheduleStore(dir string, codec Codec) (*FileScheduleStore, error) {
+	if codec == nil {
+		codec = JSONCodec{}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create schedule directory: %w", err)
+	}
+	return &FileScheduleStore{
+		mutex: &sync.Mutex{},
+		dir:   dir,
+		codec: codec,
+	}, nil
+}
+
+func (s *FileScheduleStore) path(id string) string {
+	return filepath.Join(s.dir, id+scheduleFileSuffix)
+}
+
+func (s *FileScheduleStore) Save(scheduled *ScheduledEvent) error {
+	envelope, err := EncodeEnvelope(s.codec, scheduled.Envelope)
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled event: %w", err)
+	}
+	data, err := json.Marshal(scheduledRecord{DueAt: scheduled.DueAt, Envelope: envelope})
+	if err != nil {
+		return fmt.Errorf("failed to encode scheduled event: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.WriteFile(s.path(scheduled.Envelope.ID), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write scheduled event: %w", err)
+	}
+	return nil
+}
+
+func (s *FileScheduleStore) Delete(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove scheduled event: %w", err)
+	}
+	return nil
+}
+
+func (s *FileScheduleStore) Load() ([]*ScheduledEvent, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schedule directory: %w", err)
+	}
+
+	var scheduled []*ScheduledEvent
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), scheduleFileSuffix) {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read scheduled event: %w", err)
+		}
+		var record scheduledRecord
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, fmt.Errorf("failed to decode scheduled event %s: %w", entry.Name(), err)
+		}
+		envelope, err := DecodeEnvelope(s.codec, record.Envelope)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode scheduled event %s: %w", entry.Name(), err)
+		}
+		scheduled = append(scheduled, &ScheduledEvent{DueAt: record.DueAt, Envelope: envelope})
+	}
+	return scheduled, nil
+}
+
+type scheduledQueue []*ScheduledEvent
+
+func (q scheduledQueue) Len() int            { return len(q) }
+func (q scheduledQueue) Less(i, j int) bool  { return q[i].DueAt.Before(q[j].DueAt) }
+func (q scheduledQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *scheduledQueue) Push(x interface{}) { *q = append(*q, x.(*ScheduledEvent)) }
+func (q *scheduledQueue) Pop() interface{} {
+	old := *q
+	last := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return last
+}
+
+type delayScheduler struct {
+	mutex  *sync.Mutex
+	queue  scheduledQueue
+	store  ScheduleStore
+	wakeup chan struct{}
+}
+
+func newDelayScheduler(store ScheduleStore) (*delayScheduler, error) {
+	scheduler := &delayScheduler{
+		mutex:  &sync.Mutex{},
+		store:  store,
+		wakeup: make(chan struct{}, 1),
+	}
+	if store != nil {
+		scheduled, err := store.Load()
+		if err != nil {
+			return nil, err
+		}
+		for _, event := range scheduled {
+			heap.Push(&scheduler.queue, event)
+		}
+	}
+	return scheduler, nil
+}
+
+func (s *delayScheduler) schedule(scheduled *ScheduledEvent) error {
+	if s.store != nil {
+		if err := s.store.Save(scheduled); err != nil {
+			return err
+		}
+	}
+	s.push(scheduled)
+	return nil
+}
+
+func (s *delayScheduler) push(scheduled *ScheduledEvent) {
+	s.mutex.Lock()
+	heap.Push(&s.queue, scheduled)
+	s.mutex.Unlock()
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (s *delayScheduler) popDue(now time.Time) ([]*ScheduledEvent, time.Time) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	var due []*ScheduledEvent
+	for len(s.queue) > 0 && !s.queue[0].DueAt.After(now) {
+		due = append(due, heap.Pop(&s.queue).(*ScheduledEvent))
+	}
+	if len(s.queue) == 0 {
+		return due, time.Time{}
+	}
+	return due, s.queue[0].DueAt
+}
+
+func (s *delayScheduler) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.queue)
+}
+
+func (eb *EventBus) PublishAt(at time.Time, name string, payload interface{}, opts ...PublishOption) error {
+	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
+	defer cancel()
+	publish := eb.publishChain(func(ctx context.Context, envelope *EventPayload) error {
+		return eb.scheduler.schedule(&ScheduledEvent{DueAt: at, Envelope: envelope})
+	})
+	return publish(ctx, NewEnvelope(name, payload, opts...))
+}
+
+func (eb *EventBus) PublishAfter(delay time.Duration, name string, payload interface{}, opts ...PublishOption) error {
+	if delay < 0 {
+		return errors.New("delay cannot be negative")
+	}
+	return eb.PublishAt(time.Now().Add(delay), name, payload, opts...)
+}
+
+func (eb *EventBus) runScheduler() {
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		due, next := eb.scheduler.popDue(time.Now())
+		for _, scheduled := range due {
+			eb.deliverScheduled(scheduled)
+		}
+
+		wait := time.Hour
+		if !next.IsZero() {
+			wait = time.Until(next)
+		}
+		timer.Reset(wait)
+		select {
+		case <-eb.stopChannel:
+			return
+		case <-timer.C:
+		case <-eb.scheduler.wakeup:
+		}
+	}
+}
+
+func (eb *EventBus) deliverScheduled(scheduled *ScheduledEvent) {
+	ctx, cancel := context.WithTimeout(context.Background(), eb.config.Timeout)
+	defer cancel()
+
+	err := eb.enqueue(ctx, scheduled.Envelope)
+	switch {
+	case errors.Is(err, ErrBusStopped):
+		return
+	case errors.Is(err, ErrEventDropped):
+		eb.reportError(ctx, StageSchedule, scheduled.Envelope, err)
+	case err != nil:
+		if !scheduled.retrying {
+			scheduled.retrying = true
+			eb.reportError(ctx, StageSchedule, scheduled.Envelope, err)
+		}
+		scheduled.DueAt = time.Now().Add(eb.config.FlushInterval)
+		eb.scheduler.push(scheduled)
+		return
+	}
+	if eb.scheduler.store != nil {
+		if err := eb.scheduler.store.Delete(scheduled.Envelope.ID); err != nil {
+			eb.reportError(ctx, StageSchedule, scheduled.Envelope, err)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package eventbus
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDelaySchedulerPopDue(t *testing.T) {
+	scheduler, err := newDelayScheduler(nil)
+	assert.NoError(t, err)
+	now := time.Now()
+
+	assert.NoError(t, scheduler.schedule(&ScheduledEvent{DueAt: now.Add(time.Hour), Envelope: &EventPayload{Name: "later"}}))
+	assert.NoError(t, scheduler.schedule(&ScheduledEvent{DueAt: now.Add(-time.Second), Envelope: &EventPayload{Name: "second"}}))
+	assert.NoError(t, scheduler.schedule(&ScheduledEvent{DueAt: now.Add(-time.Minute), Envelope: &EventPayload{Name: "first"}}))
+
+	due, next := scheduler.popDue(now)
+	if assert.Len(t, due, 2) {
+		assert.Equal(t, "first", due[0].Envelope.Name, "Eventos devem ser entregues na ordem de vencimento")
+		assert.Equal(t, "second", due[1].Envelope.Name)
+	}
+	assert.Equal(t, now.Add(time.Hour), next)
+	assert.Equal(t, 1, scheduler.Len())
+}
+
+func TestPublishAfterDeliversWhenDue(t *testing.T) {
+	eventBus, _ := New(WithBatchSize(1))
+	delivered := make(chan time.Time, 1)
+	eventBus.Register([]*Event{{
+		Name: "send_reminder",
+		Handler: func(payload interface{}) (interface{}, error) {
+			delivered <- time.Now()
+			return nil, nil
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	publishedAt := time.Now()
+	assert.NoError(t, eventBus.PublishAfter(50*time.Millisecond, "send_reminder", nil))
+
+	select {
+	case at := <-delivered:
+		assert.GreaterOrEqual(t, at.Sub(publishedAt), 50*time.Millisecond, "O evento não deve ser entregue antes do prazo")
+	case <-time.After(time.Second):
+		t.Fatal("O evento agendado deveria ter sido entregue")
+	}
+}
+
+func TestPublishAfterRejectsNegativeDelay(t *testing.T) {
+	eventBus, _ := New()
+	assert.EqualError(t, eventBus.PublishAfter(-time.Second, "send_reminder", nil), "delay cannot be negative")
+}
+
+func TestScheduledEventsSurviveRestart(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewFileScheduleStore(dir, nil)
+	assert.NoError(t, err)
+
+	first, _ := New(WithScheduleStore(store))
+	assert.NoError(t, first.PublishAt(time.Now().Add(50*time.Millisecond), "send_reminder", map[string]interface{}{"user": "42"}, WithOrderingKey("user-42")))
+
+	reopened, err := NewFileScheduleStore(dir, nil)
+	assert.NoError(t, err)
+	second, err := New(WithScheduleStore(reopened), WithBatchSize(1))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, second.scheduler.Len(), "Eventos agendados devem ser recarregados do store")
+
+	delivered := make(chan interface{}, 1)
+	second.Register([]*Event{{
+		Name: "send_reminder",
+		Handler: func(payload interface{}) (interface{}, error) {
+			delivered <- payload
+			return nil, nil
+		},
+	}})
+	second.Start()
+	defer second.Stop()
+
+	select {
+	case payload := <-delivered:
+		assert.Equal(t, map[string]interface{}{"user": "42"}, payload)
+	case <-time.After(time.Second):
+		t.Fatal("O evento recarregado deveria ter sido entregue")
+	}
+	assert.Eventually(t, func() bool {
+		scheduled, err := reopened.Load()
+		return err == nil && len(scheduled) == 0
+	}, time.Second, 10*time.Millisecond, "O evento entregue deve ser removido do store")
+}
+
+func fillRequestLanes(eventBus *EventBus) {
+	for _, lane := range eventBus.requestLanes {
+		for len(lane) < cap(lane) {
+			lane <- NewEnvelope("filler", nil)
+		}
+	}
+}
+
+func TestScheduledDeliveryDropIsFinal(t *testing.T) {
+	store, err := NewFileScheduleStore(t.TempDir(), nil)
+	assert.NoError(t, err)
+	eventBus, _ := New(WithScheduleStore(store), WithRequestQueueSize(1), WithOverflowPolicy(OverflowDropNewest))
+	var reported int32
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { atomic.AddInt32(&reported, 1) })
+	fillRequestLanes(eventBus)
+
+	assert.NoError(t, eventBus.PublishAt(time.Now(), "send_reminder", nil))
+	due, _ := eventBus.scheduler.popDue(time.Now())
+	eventBus.deliverScheduled(due[0])
+
+	assert.Zero(t, eventBus.scheduler.Len(), "Eventos descartados não devem ser reagendados")
+	scheduled, err := store.Load()
+	assert.NoError(t, err)
+	assert.Empty(t, scheduled, "Eventos descartados devem ser removidos do store")
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reported))
+}
+
+func TestScheduledDeliveryReportsFullQueueOnce(t *testing.T) {
+	eventBus, _ := New(WithRequestQueueSize(1))
+	var reported int32
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) {
+		assert.ErrorIs(t, info.Err, ErrQueueFull)
+		atomic.AddInt32(&reported, 1)
+	})
+	fillRequestLanes(eventBus)
+
+	assert.NoError(t, eventBus.PublishAt(time.Now(), "send_reminder", nil))
+	for i := 0; i < 3; i++ {
+		due, _ := eventBus.scheduler.popDue(time.Now().Add(time.Hour))
+		if assert.Len(t, due, 1, "Eventos com a fila cheia devem ser reagendados") {
+			eventBus.deliverScheduled(due[0])
+		}
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&reported), "A fila cheia deve ser reportada uma única vez")
+}
+
+func TestScheduledDeliveryStopsAfterStop(t *testing.T) {
+	store, err := NewFileScheduleStore(t.TempDir(), nil)
+	assert.NoError(t, err)
+	eventBus, _ := New(WithScheduleStore(store))
+	var reported int32
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { atomic.AddInt32(&reported, 1) })
+
+	assert.NoError(t, eventBus.PublishAt(time.Now(), "send_reminder", nil))
+	eventBus.Stop()
+	due, _ := eventBus.scheduler.popDue(time.Now())
+	eventBus.deliverScheduled(due[0])
+
+	assert.Zero(t, eventBus.scheduler.Len(), "Eventos não devem ser reagendados após o Stop")
+	assert.Zero(t, atomic.LoadInt32(&reported))
+	scheduled, err := store.Load()
+	assert.NoError(t, err)
+	assert.Len(t, scheduled, 1, "O evento deve continuar no store para o próximo start")
+}
@@ -0,0 +1,142 @@
+package eventbus
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	spillFileSuffix    = ".event"
+	spillCorruptSuffix = ".corrupt"
+)
+
+type diskSpill struct {
+	mutex   *sync.Mutex
+	dir     string
+	codec   Codec
+	head    uint64
+	tail    uint64
+	futures map[uint64]*PublishFuture
+}
+
+func newDiskSpill(dir string, codec Codec) (*diskSpill, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create spill directory: %w", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read spill directory: %w", err)
+	}
+
+	var sequences []uint64
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, spillFileSuffix) {
+			continue
+		}
+		sequence, err := strconv.ParseUint(strings.TrimSuffix(name, spillFileSuffix), 10, 64)
+		if err != nil {
+			continue
+		}
+		sequences = append(sequences, sequence)
+	}
+	sort.Slice(sequences, func(i, j int) bool { return sequences[i] < sequences[j] })
+
+	spill := &diskSpill{
+		mutex:   &sync.Mutex{},
+		dir:     dir,
+		codec:   codec,
+		futures: make(map[uint64]*PublishFuture),
+	}
+	if len(sequences) > 0 {
+		spill.head = sequences[0]
+		spill.tail = sequences[len(sequences)-1] + 1
+	}
+	return spill, nil
+}
+
+func (s *diskSpill) path(sequence uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%020d%s", sequence, spillFileSuffix))
+}
+
+func (s *diskSpill) Len() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return int(s.tail - s.head)
+}
+
+func (s *diskSpill) Push(eventPayload *EventPayload) error {
+	data, err := EncodeEnvelope(s.codec, eventPayload)
+	if err != nil {
+		return fmt.Errorf("failed to encode spilled event: %w", err)
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := os.WriteFile(s.path(s.tail), data, 0o644); err != nil {
+		return fmt.Errorf("failed to write spilled event: %w", err)
+	}
+	if eventPayload.future != nil {
+		s.futures[s.tail] = eventPayload.future
+	}
+	s.tail++
+	return nil
+}
+
+func (s *diskSpill) Pop() (*EventPayload, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for s.head < s.tail {
+		sequence := s.head
+		data, err := os.ReadFile(s.path(sequence))
+		if os.IsNotExist(err) {
+			s.head++
+			continue
+		}
+		if err != nil {
+			return nil, s.quarantine(sequence, fmt.Errorf("failed to read spilled event: %w", err))
+		}
+
+		eventPayload, err := DecodeEnvelope(s.codec, data)
+		if err != nil {
+			return nil, s.quarantine(sequence, fmt.Errorf("failed to decode spilled event: %w", err))
+		}
+
+		s.head++
+		eventPayload.future = s.futures[sequence]
+		delete(s.futures, sequence)
+		if err := os.Remove(s.path(sequence)); err != nil {
+			return eventPayload, fmt.Errorf("failed to remove spilled event: %w", err)
+		}
+		return eventPayload, nil
+	}
+	return nil, nil
+}
+
+func (s *diskSpill) abandon(err error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for sequence, future := range s.futures {
+		future.resolve(err)
+		delete(s.futures, sequence)
+	}
+}
+
+func (s *diskSpill) quarantine(sequence uint64, cause error) error {
+	s.head++
+	if future, ok := s.futures[sequence]; ok {
+		future.resolve(cause)
+		delete(s.futures, sequence)
+	}
+	path := s.path(sequence)
+	quarantined := strings.TrimSuffix(path, spillFileSuffix) + spillCorruptSuffix
+	if err := os.Rename(path, quarantined); err != nil {
+		return fmt.Errorf("%w (failed to quarantine %s: %w)", cause, path, err)
+	}
+	return fmt.Errorf("%w (quarantined as %s)", cause, quarantined)
+}
@@ -0,0 +1,96 @@
+package eventbus
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskSpillFIFO(t *testing.T) {
+	spill, err := newDiskSpill(t.TempDir(), JSONCodec{})
+	assert.NoError(t, err)
+
+	future := newPublishFuture()
+	assert.NoError(t, spill.Push(&EventPayload{ID: "1", CorrelationID: "c1", Name: "first", Payload: "a", future: future}))
+	assert.NoError(t, spill.Push(&EventPayload{Name: "second", Payload: "b"}))
+	assert.Equal(t, 2, spill.Len())
+
+	eventPayload, err := spill.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", eventPayload.Name)
+	assert.Equal(t, "1", eventPayload.ID)
+	assert.Equal(t, "c1", eventPayload.CorrelationID)
+	assert.Equal(t, "a", eventPayload.Payload)
+	assert.Same(t, future, eventPayload.future, "O future deve acompanhar o evento derramado")
+
+	eventPayload, err = spill.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", eventPayload.Name)
+
+	eventPayload, err = spill.Pop()
+	assert.NoError(t, err)
+	assert.Nil(t, eventPayload, "Pop deve retornar nil quando não há eventos")
+	assert.Equal(t, 0, spill.Len())
+}
+
+func TestDiskSpillSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	spill, err := newDiskSpill(dir, JSONCodec{})
+	assert.NoError(t, err)
+	assert.NoError(t, spill.Push(&EventPayload{Name: "first", Payload: 1}))
+	assert.NoError(t, spill.Push(&EventPayload{Name: "second", Payload: 2}))
+
+	reopened, err := newDiskSpill(dir, JSONCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, reopened.Len())
+
+	eventPayload, err := reopened.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, "first", eventPayload.Name)
+	assert.Equal(t, float64(1), eventPayload.Payload)
+}
+
+type failingCodec struct {
+	JSONCodec
+}
+
+func (failingCodec) Marshal(v interface{}) ([]byte, error) {
+	return nil, errors.New("unsupported payload")
+}
+
+func TestDiskSpillEncodeError(t *testing.T) {
+	spill, err := newDiskSpill(t.TempDir(), failingCodec{})
+	assert.NoError(t, err)
+
+	err = spill.Push(&EventPayload{Name: "first"})
+	assert.EqualError(t, err, "failed to encode spilled event: failed to encode payload: unsupported payload")
+	assert.Equal(t, 0, spill.Len())
+}
+
+func TestDiskSpillQuarantinesCorruptFile(t *testing.T) {
+	dir := t.TempDir()
+	spill, err := newDiskSpill(dir, JSONCodec{})
+	assert.NoError(t, err)
+	future := newPublishFuture()
+	assert.NoError(t, spill.Push(&EventPayload{Name: "first", future: future}))
+	assert.NoError(t, spill.Push(&EventPayload{Name: "second"}))
+	assert.NoError(t, os.WriteFile(spill.path(0), []byte("garbage"), 0o644))
+
+	eventPayload, err := spill.Pop()
+	assert.ErrorContains(t, err, "failed to decode spilled event")
+	assert.Nil(t, eventPayload)
+	assert.Error(t, future.Err(), "O future do evento corrompido deve ser resolvido com erro")
+	assert.FileExists(t, filepath.Join(dir, "00000000000000000000.corrupt"), "O arquivo corrompido deve ir para quarentena")
+
+	eventPayload, err = spill.Pop()
+	assert.NoError(t, err)
+	assert.Equal(t, "second", eventPayload.Name, "O spill deve seguir após o arquivo corrompido")
+	assert.Equal(t, 0, spill.Len())
+
+	reopened, err := newDiskSpill(dir, JSONCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, reopened.Len(), "Arquivos em quarentena não devem ser relidos")
+}
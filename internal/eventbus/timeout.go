@@ -0,0 +1,73 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type TimeoutError struct {
+	Event   string
+	Timeout time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("handler for event %s timed out after %s", e.Event, e.Timeout)
+}
+
+func (e *TimeoutError) Unwrap() error {
+	return context.DeadlineExceeded
+}
+
+type handlerResult struct {
+	output    interface{}
+	err       error
+	recovered interface{}
+	panicked  bool
+}
+
+func (eb *EventBus) handlerTimeout(name string) time.Duration {
+	if timeout, ok := eb.config.HandlerTimeouts[name]; ok {
+		return timeout
+	}
+	return eb.config.Timeout
+}
+
+func (eb *EventBus) callHandler(ctx context.Context, e *Event, timeout time.Duration, handler HandlerFunc, envelope *EventPayload) (interface{}, error) {
+	done := make(chan handlerResult, 1)
+	running, tracked := ctx.Value(runningHandlerKey{}).(*sync.WaitGroup)
+	if tracked {
+		running.Add(1)
+	}
+	go func() {
+		if tracked {
+			defer running.Done()
+		}
+		result := handlerResult{}
+		defer func() {
+			if r := recover(); r != nil {
+				result = handlerResult{recovered: r, panicked: true}
+			}
+			done <- result
+		}()
+		result.output, result.err = handler(ctx, envelope)
+	}()
+
+	select {
+	case result := <-done:
+		if result.panicked {
+			panic(result.recovered)
+		}
+		return result.output, result.err
+	case <-ctx.Done():
+		if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, ctx.Err()
+		}
+		eb.timeoutCounter.Add(ctx, 1, eb.metricAttrs(attribute.String("event_name", e.Name)))
+		return nil, &TimeoutError{Event: e.Name, Timeout: timeout}
+	}
+}
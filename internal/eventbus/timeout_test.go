@@ -0,0 +1,168 @@
+package eventbus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestHandlerTimeoutReleasesWorkerSlot(t *testing.T) {
+	reader := sdkmetric.NewManualReader()
+	eventBus, err := New(
+		WithWorkerPoolSize(1),
+		WithHandlerTimeout("stuck", 20*time.Millisecond),
+		WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	assert.NoError(t, err)
+
+	block := make(chan struct{})
+	defer close(block)
+	handled := make(chan interface{}, 1)
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	eventBus.Register([]*Event{
+		{Name: "stuck", Handler: func(payload interface{}) (interface{}, error) { <-block; return nil, nil }},
+		{Name: "next", Handler: func(payload interface{}) (interface{}, error) { handled <- payload; return nil, nil }},
+	})
+
+	eventBus.ProcessEvent("stuck", 1)
+	eventBus.ProcessEvent("next", 2)
+	select {
+	case payload := <-handled:
+		assert.Equal(t, 2, payload, "O slot do worker deve ser liberado após o timeout")
+	case <-time.After(time.Second):
+		t.Fatal("O handler travado não deveria ocupar o worker pool")
+	}
+
+	info := <-failures
+	var timeoutErr *TimeoutError
+	if assert.ErrorAs(t, info.Err, &timeoutErr) {
+		assert.Equal(t, "stuck", timeoutErr.Event)
+		assert.Equal(t, 20*time.Millisecond, timeoutErr.Timeout)
+	}
+	assert.ErrorIs(t, info.Err, context.DeadlineExceeded)
+	assert.Equal(t, StageHandler, info.Stage)
+
+	points := collectSums(t, reader, "eventbus.handler.timeouts")
+	if assert.Len(t, points, 1) {
+		assert.Equal(t, int64(1), points[0].Value)
+	}
+}
+
+func TestContextHandlerObservesCancellation(t *testing.T) {
+	eventBus, _ := New(WithTimeout(20 * time.Millisecond))
+	cancelled := make(chan error, 1)
+	eventBus.Register([]*Event{{
+		Name: "sync_crm",
+		HandlerContext: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return nil, ctx.Err()
+		},
+	}})
+
+	eventBus.ProcessEvent("sync_crm", 1)
+	select {
+	case err := <-cancelled:
+		assert.ErrorIs(t, err, context.DeadlineExceeded, "Handlers com contexto devem ser cancelados no timeout")
+	case <-time.After(time.Second):
+		t.Fatal("O contexto do handler deveria ser cancelado")
+	}
+}
+
+func TestContextHandlerReceivesPayload(t *testing.T) {
+	eventBus, _ := New()
+	handled := make(chan interface{}, 1)
+	eventBus.Register([]*Event{{
+		Name: "sync_crm",
+		HandlerContext: func(ctx context.Context, payload interface{}) (interface{}, error) {
+			_, ok := ctx.Deadline()
+			assert.True(t, ok, "O contexto do handler deve ter deadline")
+			handled <- payload
+			return nil, nil
+		},
+	}})
+
+	eventBus.ProcessEvent("sync_crm", "customer-1")
+	select {
+	case payload := <-handled:
+		assert.Equal(t, "customer-1", payload)
+	case <-time.After(time.Second):
+		t.Fatal("O handler com contexto deveria ser chamado")
+	}
+}
+
+func TestHandlerTimeoutSaga(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		enabled bool
+	}{
+		{name: "disabled", enabled: false},
+		{name: "enabled", enabled: true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eventBus, _ := New(WithBatchSize(1), WithTimeout(20*time.Millisecond), WithSagaOnTimeout(tc.enabled))
+			block := make(chan struct{})
+			defer close(block)
+			saga := "refund"
+			compensated := make(chan struct{}, 1)
+			failures := make(chan ErrorInfo, 1)
+			eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+			eventBus.Register([]*Event{
+				{Name: "charge", Saga: &saga, Handler: func(payload interface{}) (interface{}, error) { <-block; return nil, nil }},
+				{Name: "refund", Handler: func(payload interface{}) (interface{}, error) { compensated <- struct{}{}; return nil, nil }},
+			})
+			eventBus.Start()
+			defer eventBus.Stop()
+
+			eventBus.ProcessEvent("charge", 1)
+			if tc.enabled {
+				select {
+				case <-compensated:
+				case <-time.After(time.Second):
+					t.Fatal("A saga deveria ser disparada no timeout")
+				}
+				return
+			}
+			select {
+			case info := <-failures:
+				assert.ErrorAs(t, info.Err, new(*TimeoutError))
+			case <-time.After(time.Second):
+				t.Fatal("O timeout deveria ser reportado")
+			}
+			select {
+			case <-compensated:
+				t.Fatal("A saga não deve ser disparada no timeout por padrão")
+			case <-time.After(50 * time.Millisecond):
+			}
+		})
+	}
+}
+
+func TestHandlerPanicStillRecovered(t *testing.T) {
+	eventBus, _ := New()
+	failures := make(chan ErrorInfo, 1)
+	eventBus.OnError(func(ctx context.Context, info ErrorInfo) { failures <- info })
+	eventBus.Register([]*Event{{Name: "boom", Handler: func(payload interface{}) (interface{}, error) { panic("kaboom") }}})
+
+	eventBus.ProcessEvent("boom", 1)
+	select {
+	case info := <-failures:
+		var panicErr *PanicError
+		if assert.ErrorAs(t, info.Err, &panicErr) {
+			assert.Equal(t, "kaboom", panicErr.Value)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("O panic do handler deveria ser reportado")
+	}
+}
+
+func TestWithHandlerTimeoutValidates(t *testing.T) {
+	_, err := New(WithHandlerTimeout("stuck", 0))
+	assert.EqualError(t, err, "handler timeout for event stuck must be positive, got 0s")
+	_, err = NewEventBus(nil, nil, EventBusConfig{HandlerTimeouts: map[string]time.Duration{"stuck": -time.Second}})
+	assert.EqualError(t, err, "handler timeout for event stuck must be positive, got -1s")
+}
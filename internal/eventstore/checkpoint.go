@@ -0,0 +1,85 @@
+package eventstore
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/salesof7/eventbus/internal/jsonfile"
+)
+
+type CheckpointStore interface {
+	Checkpoint(ctx context.Context, projection string) (int64, error)
+	SaveCheckpoint(ctx context.Context, projection string, position int64) error
+}
+
+type MemoryCheckpointStore struct {
+	mutex       *sync.Mutex
+	checkpoints map[string]int64
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{
+		mutex:       &sync.Mutex{},
+		checkpoints: make(map[string]int64),
+	}
+}
+
+func (s *MemoryCheckpointStore) Checkpoint(ctx context.Context, projection string) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.checkpoints[projection], nil
+}
+
+func (s *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, projection string, position int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.checkpoints[projection] = position
+	return nil
+}
+
+type FileCheckpointStore struct {
+	mutex *sync.Mutex
+	path  string
+}
+
+func NewFileCheckpointStore(path string) (*FileCheckpointStore, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create checkpoint directory: %w", err)
+	}
+	return &FileCheckpointStore{
+		mutex: &sync.Mutex{},
+		path:  path,
+	}, nil
+}
+
+func (s *FileCheckpointStore) read() (map[string]int64, error) {
+	checkpoints := make(map[string]int64)
+	if err := jsonfile.Load(s.path, "checkpoints", &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func (s *FileCheckpointStore) Checkpoint(ctx context.Context, projection string) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return 0, err
+	}
+	return checkpoints[projection], nil
+}
+
+func (s *FileCheckpointStore) SaveCheckpoint(ctx context.Context, projection string, position int64) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	checkpoints, err := s.read()
+	if err != nil {
+		return err
+	}
+	checkpoints[projection] = position
+	return jsonfile.Save(s.path, "checkpoints", checkpoints)
+}
@@ -0,0 +1,29 @@
+package eventstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileCheckpointStorePersistsPositions(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "checkpoints.json")
+	store, err := NewFileCheckpointStore(path)
+	assert.NoError(t, err)
+
+	checkpoint, err := store.Checkpoint(ctx, "orders")
+	assert.NoError(t, err)
+	assert.Zero(t, checkpoint, "Projeções sem checkpoint devem começar do início")
+
+	assert.NoError(t, store.SaveCheckpoint(ctx, "orders", 42))
+	assert.NoError(t, store.SaveCheckpoint(ctx, "revenue", 7))
+
+	reopened, _ := NewFileCheckpointStore(path)
+	checkpoint, _ = reopened.Checkpoint(ctx, "orders")
+	assert.Equal(t, int64(42), checkpoint)
+	checkpoint, _ = reopened.Checkpoint(ctx, "revenue")
+	assert.Equal(t, int64(7), checkpoint)
+}
@@ -0,0 +1,126 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+const (
+	AnyVersion int64 = -1
+	NoStream   int64 = 0
+)
+
+const (
+	HeaderStreamID      = "stream_id"
+	HeaderStreamVersion = "stream_version"
+	HeaderPosition      = "stream_position"
+)
+
+var (
+	ErrWrongExpectedVersion = errors.New("wrong expected version")
+	ErrEmptyStreamID        = errors.New("stream id cannot be empty")
+)
+
+type Direction int
+
+const (
+	Forward Direction = iota
+	Backward
+)
+
+type RecordedEvent struct {
+	StreamID   string
+	Version    int64
+	Position   int64
+	RecordedAt time.Time
+	Envelope   *eventbus.EventPayload
+}
+
+type Store interface {
+	Append(ctx context.Context, streamID string, expectedVersion int64, events ...*eventbus.EventPayload) ([]*RecordedEvent, error)
+	ReadStream(ctx context.Context, streamID string, from int64, direction Direction, limit int) ([]*RecordedEvent, error)
+	ReadAll(ctx context.Context, from int64, direction Direction, limit int) ([]*RecordedEvent, error)
+	StreamVersion(ctx context.Context, streamID string) (int64, error)
+	Head(ctx context.Context) (int64, error)
+}
+
+func checkExpectedVersion(streamID string, expected, actual int64) error {
+	if expected == AnyVersion || expected == actual {
+		return nil
+	}
+	return fmt.Errorf("%w: stream %s expected version %d, got %d", ErrWrongExpectedVersion, streamID, expected, actual)
+}
+
+func copyEnvelope(envelope *eventbus.EventPayload) *eventbus.EventPayload {
+	copied := &eventbus.EventPayload{
+		ID:            envelope.ID,
+		CorrelationID: envelope.CorrelationID,
+		Name:          envelope.Name,
+		Key:           envelope.Key,
+		Priority:      envelope.Priority,
+		Payload:       envelope.Payload,
+	}
+	if envelope.Headers != nil {
+		copied.Headers = make(map[string]string, len(envelope.Headers))
+		for key, value := range envelope.Headers {
+			copied.Headers[key] = value
+		}
+	}
+	return copied
+}
+
+func copyRecords(records []*RecordedEvent) []*RecordedEvent {
+	copied := make([]*RecordedEvent, len(records))
+	for i, record := range records {
+		clone := *record
+		clone.Envelope = copyEnvelope(record.Envelope)
+		copied[i] = &clone
+	}
+	return copied
+}
+
+func stamp(record *RecordedEvent) {
+	envelope := record.Envelope
+	headers := make(map[string]string, len(envelope.Headers)+3)
+	for key, value := range envelope.Headers {
+		headers[key] = value
+	}
+	headers[HeaderStreamID] = record.StreamID
+	headers[HeaderStreamVersion] = strconv.FormatInt(record.Version, 10)
+	headers[HeaderPosition] = strconv.FormatInt(record.Position, 10)
+	envelope.Headers = headers
+	if envelope.Key == "" {
+		envelope.Key = record.StreamID
+	}
+}
+
+func selectRange(records []*RecordedEvent, from int64, direction Direction, limit int, index func(*RecordedEvent) int64) []*RecordedEvent {
+	var selected []*RecordedEvent
+	if direction == Backward {
+		for i := len(records) - 1; i >= 0; i-- {
+			if from > 0 && index(records[i]) > from {
+				continue
+			}
+			selected = append(selected, records[i])
+			if limit > 0 && len(selected) == limit {
+				break
+			}
+		}
+		return selected
+	}
+	for _, record := range records {
+		if index(record) < from {
+			continue
+		}
+		selected = append(selected, record)
+		if limit > 0 && len(selected) == limit {
+			break
+		}
+	}
+	return selected
+}
@@ -0,0 +1,156 @@
+package eventstore
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func testStores(t *testing.T) map[string]Store {
+	fileStore, err := NewFileStore(filepath.Join(t.TempDir(), "events.log"), nil)
+	assert.NoError(t, err)
+	t.Cleanup(func() { fileStore.Close() })
+	return map[string]Store{
+		"memory": NewMemoryStore(),
+		"file":   fileStore,
+	}
+}
+
+func events(names ...string) []*eventbus.EventPayload {
+	envelopes := make([]*eventbus.EventPayload, len(names))
+	for i, name := range names {
+		envelopes[i] = eventbus.NewEnvelope(name, map[string]interface{}{"step": name})
+	}
+	return envelopes
+}
+
+func names(records []*RecordedEvent) []string {
+	result := make([]string, len(records))
+	for i, record := range records {
+		result[i] = record.Envelope.Name
+	}
+	return result
+}
+
+func TestAppendAssignsVersionsAndPositions(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			records, err := store.Append(ctx, "order-1", NoStream, events("order_created", "item_added")...)
+			assert.NoError(t, err)
+			_, err = store.Append(ctx, "order-2", NoStream, events("order_created")...)
+			assert.NoError(t, err)
+			more, err := store.Append(ctx, "order-1", 2, events("order_paid")...)
+			assert.NoError(t, err)
+			records = append(records, more...)
+
+			assert.Equal(t, []int64{1, 2, 3}, []int64{records[0].Version, records[1].Version, records[2].Version})
+			assert.Equal(t, []int64{1, 2, 4}, []int64{records[0].Position, records[1].Position, records[2].Position})
+			assert.Equal(t, "order-1", records[2].Envelope.Key, "O stream deve ser usado como chave de ordenação")
+			assert.Equal(t, "3", records[2].Envelope.Headers[HeaderStreamVersion])
+			assert.Equal(t, "4", records[2].Envelope.Headers[HeaderPosition])
+
+			version, _ := store.StreamVersion(ctx, "order-1")
+			assert.Equal(t, int64(3), version)
+			head, _ := store.Head(ctx)
+			assert.Equal(t, int64(4), head)
+		})
+	}
+}
+
+func TestStoredEventsAreIsolatedFromCallers(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			envelope := eventbus.NewEnvelope("order_created", nil)
+			records, err := store.Append(ctx, "order-1", NoStream, envelope)
+			assert.NoError(t, err)
+			assert.Empty(t, envelope.Key, "O envelope do chamador não deve ser alterado")
+			assert.NotContains(t, envelope.Headers, HeaderStreamID)
+
+			envelope.Name = "mutated"
+			records[0].Envelope.Priority = eventbus.PriorityHigh
+			records[0].Envelope.Headers[HeaderStreamVersion] = "99"
+			read, err := store.ReadStream(ctx, "order-1", 0, Forward, 0)
+			assert.NoError(t, err)
+			read[0].Envelope.Key = "mutated"
+
+			stored, err := store.ReadAll(ctx, 0, Forward, 0)
+			assert.NoError(t, err)
+			assert.Equal(t, "order_created", stored[0].Envelope.Name)
+			assert.Equal(t, "order-1", stored[0].Envelope.Key)
+			assert.Zero(t, stored[0].Envelope.Priority, "Alterações nas cópias não devem afetar o histórico")
+			assert.Equal(t, "1", stored[0].Envelope.Headers[HeaderStreamVersion])
+		})
+	}
+}
+
+func TestAppendRejectsWrongExpectedVersion(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Append(ctx, "order-1", NoStream, events("order_created")...)
+			assert.NoError(t, err)
+
+			_, err = store.Append(ctx, "order-1", NoStream, events("order_created")...)
+			assert.ErrorIs(t, err, ErrWrongExpectedVersion)
+			assert.EqualError(t, err, "wrong expected version: stream order-1 expected version 0, got 1")
+
+			_, err = store.Append(ctx, "order-1", AnyVersion, events("order_paid")...)
+			assert.NoError(t, err)
+			_, err = store.Append(ctx, "", AnyVersion, events("order_paid")...)
+			assert.ErrorIs(t, err, ErrEmptyStreamID)
+		})
+	}
+}
+
+func TestReadForwardAndBackward(t *testing.T) {
+	ctx := context.Background()
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			_, err := store.Append(ctx, "order-1", NoStream, events("created", "added", "paid", "shipped")...)
+			assert.NoError(t, err)
+			_, err = store.Append(ctx, "order-2", NoStream, events("cancelled")...)
+			assert.NoError(t, err)
+
+			forward, _ := store.ReadStream(ctx, "order-1", 2, Forward, 2)
+			assert.Equal(t, []string{"added", "paid"}, names(forward))
+			backward, _ := store.ReadStream(ctx, "order-1", 0, Backward, 2)
+			assert.Equal(t, []string{"shipped", "paid"}, names(backward))
+			backwardFrom, _ := store.ReadStream(ctx, "order-1", 2, Backward, 0)
+			assert.Equal(t, []string{"added", "created"}, names(backwardFrom))
+
+			all, _ := store.ReadAll(ctx, 4, Forward, 0)
+			assert.Equal(t, []string{"shipped", "cancelled"}, names(all))
+			missing, _ := store.ReadStream(ctx, "order-3", 0, Forward, 0)
+			assert.Empty(t, missing)
+		})
+	}
+}
+
+func TestFileStoreReloadsEvents(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "events.log")
+	store, err := NewFileStore(path, nil)
+	assert.NoError(t, err)
+	_, err = store.Append(ctx, "order-1", NoStream, events("created", "paid")...)
+	assert.NoError(t, err)
+	assert.NoError(t, store.Close())
+
+	reopened, err := NewFileStore(path, nil)
+	assert.NoError(t, err)
+	defer reopened.Close()
+
+	records, _ := reopened.ReadStream(ctx, "order-1", 0, Forward, 0)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, map[string]interface{}{"step": "paid"}, records[1].Envelope.Payload)
+		assert.Equal(t, "order-1", records[1].Envelope.Headers[HeaderStreamID])
+	}
+	_, err = reopened.Append(ctx, "order-1", 2, events("shipped")...)
+	assert.NoError(t, err, "A versão do stream deve ser recuperada após reabrir o arquivo")
+	head, _ := reopened.Head(ctx)
+	assert.Equal(t, int64(3), head)
+}
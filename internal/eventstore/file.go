@@ -0,0 +1,144 @@
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type fileRecord struct {
+	StreamID   string          `json:"stream_id"`
+	Version    int64           `json:"version"`
+	Position   int64           `json:"position"`
+	RecordedAt time.Time       `json:"recorded_at"`
+	Envelope   json.RawMessage `json:"envelope"`
+}
+
+type FileStore struct {
+	*MemoryStore
+	file  *os.File
+	size  int64
+	codec eventbus.Codec
+}
+
+func NewFileStore(path string, codec eventbus.Codec) (*FileStore, error) {
+	if codec == nil {
+		codec = eventbus.JSONCodec{}
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create event store dir: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open event store: %w", err)
+	}
+	store := &FileStore{MemoryStore: NewMemoryStore(), file: file, codec: codec}
+	if err := store.load(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+func (s *FileStore) load() error {
+	reader := bufio.NewReader(s.file)
+	var offset int64
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(line) > 0 {
+				if err := s.file.Truncate(offset); err != nil {
+					return fmt.Errorf("failed to truncate partial event: %w", err)
+				}
+			}
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read event store: %w", err)
+		}
+		var record fileRecord
+		if err := json.Unmarshal(bytes.TrimSpace(line), &record); err != nil {
+			return fmt.Errorf("failed to decode event at offset %d: %w", offset, err)
+		}
+		envelope, err := eventbus.DecodeEnvelope(s.codec, record.Envelope)
+		if err != nil {
+			return err
+		}
+		s.commit([]*RecordedEvent{{
+			StreamID:   record.StreamID,
+			Version:    record.Version,
+			Position:   record.Position,
+			RecordedAt: record.RecordedAt,
+			Envelope:   envelope,
+		}})
+		offset += int64(len(line))
+	}
+	s.size = offset
+	_, err := s.file.Seek(offset, io.SeekStart)
+	return err
+}
+
+func (s *FileStore) Append(ctx context.Context, streamID string, expectedVersion int64, events ...*eventbus.EventPayload) ([]*RecordedEvent, error) {
+	if streamID == "" {
+		return nil, ErrEmptyStreamID
+	}
+	s.MemoryStore.mutex.Lock()
+	defer s.MemoryStore.mutex.Unlock()
+
+	records, err := s.prepare(streamID, expectedVersion, events)
+	if err != nil {
+		return nil, err
+	}
+	var buffer bytes.Buffer
+	for _, record := range records {
+		envelope, err := eventbus.EncodeEnvelope(s.codec, record.Envelope)
+		if err != nil {
+			return nil, err
+		}
+		line, err := json.Marshal(fileRecord{
+			StreamID:   record.StreamID,
+			Version:    record.Version,
+			Position:   record.Position,
+			RecordedAt: record.RecordedAt,
+			Envelope:   envelope,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode event: %w", err)
+		}
+		buffer.Write(line)
+		buffer.WriteByte('\n')
+	}
+	if err := s.write(buffer.Bytes()); err != nil {
+		return nil, err
+	}
+	s.commit(records)
+	return copyRecords(records), nil
+}
+
+func (s *FileStore) write(data []byte) error {
+	_, err := s.file.Write(data)
+	if err == nil {
+		err = s.file.Sync()
+	}
+	if err != nil {
+		s.file.Truncate(s.size)
+		s.file.Seek(s.size, io.SeekStart)
+		return fmt.Errorf("failed to append events: %w", err)
+	}
+	s.size += int64(len(data))
+	return nil
+}
+
+func (s *FileStore) Close() error {
+	s.MemoryStore.mutex.Lock()
+	defer s.MemoryStore.mutex.Unlock()
+	return s.file.Close()
+}
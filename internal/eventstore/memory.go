@@ -0,0 +1,89 @@
+package eventstore
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type MemoryStore struct {
+	mutex   *sync.RWMutex
+	events  []*RecordedEvent
+	streams map[string][]*RecordedEvent
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		mutex:   &sync.RWMutex{},
+		streams: make(map[string][]*RecordedEvent),
+	}
+}
+
+func (s *MemoryStore) Append(ctx context.Context, streamID string, expectedVersion int64, events ...*eventbus.EventPayload) ([]*RecordedEvent, error) {
+	if streamID == "" {
+		return nil, ErrEmptyStreamID
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	records, err := s.prepare(streamID, expectedVersion, events)
+	if err != nil {
+		return nil, err
+	}
+	s.commit(records)
+	return copyRecords(records), nil
+}
+
+func (s *MemoryStore) prepare(streamID string, expectedVersion int64, events []*eventbus.EventPayload) ([]*RecordedEvent, error) {
+	version := int64(len(s.streams[streamID]))
+	if err := checkExpectedVersion(streamID, expectedVersion, version); err != nil {
+		return nil, err
+	}
+	position := int64(len(s.events))
+	now := time.Now().UTC()
+	records := make([]*RecordedEvent, len(events))
+	for i, envelope := range events {
+		records[i] = &RecordedEvent{
+			StreamID:   streamID,
+			Version:    version + int64(i) + 1,
+			Position:   position + int64(i) + 1,
+			RecordedAt: now,
+			Envelope:   copyEnvelope(envelope),
+		}
+		stamp(records[i])
+	}
+	return records, nil
+}
+
+func (s *MemoryStore) commit(records []*RecordedEvent) {
+	for _, record := range records {
+		s.events = append(s.events, record)
+		s.streams[record.StreamID] = append(s.streams[record.StreamID], record)
+	}
+}
+
+func (s *MemoryStore) ReadStream(ctx context.Context, streamID string, from int64, direction Direction, limit int) ([]*RecordedEvent, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return copyRecords(selectRange(s.streams[streamID], from, direction, limit, func(record *RecordedEvent) int64 { return record.Version })), nil
+}
+
+func (s *MemoryStore) ReadAll(ctx context.Context, from int64, direction Direction, limit int) ([]*RecordedEvent, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return copyRecords(selectRange(s.events, from, direction, limit, func(record *RecordedEvent) int64 { return record.Position })), nil
+}
+
+func (s *MemoryStore) StreamVersion(ctx context.Context, streamID string) (int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return int64(len(s.streams[streamID])), nil
+}
+
+func (s *MemoryStore) Head(ctx context.Context) (int64, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return int64(len(s.events)), nil
+}
@@ -0,0 +1,206 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+const projectionBatchSize = 100
+
+type Projection struct {
+	Name   string
+	Events []string
+	Handle func(ctx context.Context, event *RecordedEvent) error
+	Reset  func(ctx context.Context) error
+}
+
+type ProjectionStatus struct {
+	Name       string
+	Checkpoint int64
+	Head       int64
+	Lag        int64
+}
+
+type projectionEntry struct {
+	mutex      *sync.Mutex
+	projection Projection
+	events     map[string]bool
+}
+
+type ProjectionRunner struct {
+	mutex       *sync.Mutex
+	store       Store
+	checkpoints CheckpointStore
+	projections map[string]*projectionEntry
+}
+
+func NewProjectionRunner(store Store, checkpoints CheckpointStore) *ProjectionRunner {
+	if checkpoints == nil {
+		checkpoints = NewMemoryCheckpointStore()
+	}
+	return &ProjectionRunner{
+		mutex:       &sync.Mutex{},
+		store:       store,
+		checkpoints: checkpoints,
+		projections: make(map[string]*projectionEntry),
+	}
+}
+
+func (r *ProjectionRunner) Add(projection Projection) error {
+	if projection.Name == "" {
+		return errors.New("projection name cannot be empty")
+	}
+	if projection.Handle == nil {
+		return fmt.Errorf("projection %s has no handler", projection.Name)
+	}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, exists := r.projections[projection.Name]; exists {
+		return fmt.Errorf("projection %s already registered", projection.Name)
+	}
+	events := make(map[string]bool, len(projection.Events))
+	for _, name := range projection.Events {
+		events[name] = true
+	}
+	r.projections[projection.Name] = &projectionEntry{mutex: &sync.Mutex{}, projection: projection, events: events}
+	return nil
+}
+
+func (r *ProjectionRunner) Registry() *eventbus.EventRegistry {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	subscribers := make(map[string][]string)
+	for name, entry := range r.projections {
+		for event := range entry.events {
+			subscribers[event] = append(subscribers[event], name)
+		}
+	}
+	registry := eventbus.NewEventRegistry()
+	for event, projections := range subscribers {
+		registry.Register([]*eventbus.Event{{
+			Name: event,
+			Handler: func(payload interface{}) (interface{}, error) {
+				var errs []error
+				for _, name := range projections {
+					errs = append(errs, r.CatchUpProjection(context.Background(), name))
+				}
+				return nil, errors.Join(errs...)
+			},
+		}})
+	}
+	return registry
+}
+
+func (r *ProjectionRunner) entry(name string) (*projectionEntry, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	entry, ok := r.projections[name]
+	if !ok {
+		return nil, fmt.Errorf("projection %s not found", name)
+	}
+	return entry, nil
+}
+
+func (r *ProjectionRunner) names() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	names := make([]string, 0, len(r.projections))
+	for name := range r.projections {
+		names = append(names, name)
+	}
+	return names
+}
+
+func (r *ProjectionRunner) CatchUp(ctx context.Context) error {
+	var errs []error
+	for _, name := range r.names() {
+		errs = append(errs, r.CatchUpProjection(ctx, name))
+	}
+	return errors.Join(errs...)
+}
+
+func (r *ProjectionRunner) CatchUpProjection(ctx context.Context, name string) error {
+	entry, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+	return r.catchUp(ctx, entry)
+}
+
+func (r *ProjectionRunner) catchUp(ctx context.Context, entry *projectionEntry) error {
+	name := entry.projection.Name
+	checkpoint, err := r.checkpoints.Checkpoint(ctx, name)
+	if err != nil {
+		return err
+	}
+	for {
+		records, err := r.store.ReadAll(ctx, checkpoint+1, Forward, projectionBatchSize)
+		if err != nil {
+			return err
+		}
+		saved := checkpoint
+		for _, record := range records {
+			if len(entry.events) == 0 || entry.events[record.Envelope.Name] {
+				if err := entry.projection.Handle(ctx, record); err != nil {
+					err = fmt.Errorf("projection %s failed at position %d: %w", name, record.Position, err)
+					if checkpoint > saved {
+						if saveErr := r.checkpoints.SaveCheckpoint(ctx, name, checkpoint); saveErr != nil {
+							return errors.Join(err, saveErr)
+						}
+					}
+					return err
+				}
+			}
+			checkpoint = record.Position
+		}
+		if checkpoint > saved {
+			if err := r.checkpoints.SaveCheckpoint(ctx, name, checkpoint); err != nil {
+				return err
+			}
+		}
+		if len(records) < projectionBatchSize {
+			return nil
+		}
+	}
+}
+
+func (r *ProjectionRunner) Rebuild(ctx context.Context, name string) error {
+	entry, err := r.entry(name)
+	if err != nil {
+		return err
+	}
+	entry.mutex.Lock()
+	defer entry.mutex.Unlock()
+
+	if entry.projection.Reset != nil {
+		if err := entry.projection.Reset(ctx); err != nil {
+			return fmt.Errorf("failed to reset projection %s: %w", name, err)
+		}
+	}
+	if err := r.checkpoints.SaveCheckpoint(ctx, name, 0); err != nil {
+		return err
+	}
+	return r.catchUp(ctx, entry)
+}
+
+func (r *ProjectionRunner) Status(ctx context.Context, name string) (ProjectionStatus, error) {
+	if _, err := r.entry(name); err != nil {
+		return ProjectionStatus{}, err
+	}
+	checkpoint, err := r.checkpoints.Checkpoint(ctx, name)
+	if err != nil {
+		return ProjectionStatus{}, err
+	}
+	head, err := r.store.Head(ctx)
+	if err != nil {
+		return ProjectionStatus{}, err
+	}
+	return ProjectionStatus{Name: name, Checkpoint: checkpoint, Head: head, Lag: head - checkpoint}, nil
+}
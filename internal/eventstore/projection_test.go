@@ -0,0 +1,167 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+type orderCount struct {
+	mutex  sync.Mutex
+	orders map[string]int
+}
+
+func newOrderCount() *orderCount {
+	return &orderCount{orders: make(map[string]int)}
+}
+
+func (c *orderCount) projection() Projection {
+	return Projection{
+		Name:   "order_count",
+		Events: []string{"item_added"},
+		Handle: func(ctx context.Context, event *RecordedEvent) error {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.orders[event.StreamID]++
+			return nil
+		},
+		Reset: func(ctx context.Context) error {
+			c.mutex.Lock()
+			defer c.mutex.Unlock()
+			c.orders = make(map[string]int)
+			return nil
+		},
+	}
+}
+
+func (c *orderCount) get(streamID string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.orders[streamID]
+}
+
+func TestProjectionCatchUpAdvancesCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	checkpoints := NewMemoryCheckpointStore()
+	runner := NewProjectionRunner(store, checkpoints)
+	counts := newOrderCount()
+	assert.NoError(t, runner.Add(counts.projection()))
+
+	_, _ = store.Append(ctx, "order-1", NoStream, events("item_added", "item_added", "order_paid")...)
+	status, _ := runner.Status(ctx, "order_count")
+	assert.Equal(t, ProjectionStatus{Name: "order_count", Checkpoint: 0, Head: 3, Lag: 3}, status)
+
+	assert.NoError(t, runner.CatchUp(ctx))
+	assert.Equal(t, 2, counts.get("order-1"))
+	status, _ = runner.Status(ctx, "order_count")
+	assert.Zero(t, status.Lag, "Eventos ignorados pela projeção também devem avançar o checkpoint")
+
+	_, _ = store.Append(ctx, "order-1", AnyVersion, events("item_added")...)
+	assert.NoError(t, runner.CatchUp(ctx))
+	assert.Equal(t, 3, counts.get("order-1"), "Eventos já projetados não devem ser reaplicados")
+}
+
+func TestProjectionRebuildStartsFromZero(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	runner := NewProjectionRunner(store, nil)
+	counts := newOrderCount()
+	assert.NoError(t, runner.Add(counts.projection()))
+
+	_, _ = store.Append(ctx, "order-1", NoStream, events("item_added", "item_added")...)
+	assert.NoError(t, runner.CatchUp(ctx))
+	assert.NoError(t, runner.Rebuild(ctx, "order_count"))
+	assert.Equal(t, 2, counts.get("order-1"), "A reconstrução deve limpar o read model antes de reprocessar")
+
+	assert.EqualError(t, runner.Rebuild(ctx, "missing"), "projection missing not found")
+}
+
+func TestProjectionStopsAtFailedEvent(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	runner := NewProjectionRunner(store, nil)
+	calls := 0
+	assert.NoError(t, runner.Add(Projection{
+		Name: "flaky",
+		Handle: func(ctx context.Context, event *RecordedEvent) error {
+			calls++
+			if event.Position == 2 && calls == 2 {
+				return errors.New("read model unavailable")
+			}
+			return nil
+		},
+	}))
+
+	_, _ = store.Append(ctx, "order-1", NoStream, events("item_added", "item_added", "item_added")...)
+	assert.EqualError(t, runner.CatchUp(ctx), "projection flaky failed at position 2: read model unavailable")
+	status, _ := runner.Status(ctx, "flaky")
+	assert.Equal(t, int64(1), status.Checkpoint)
+
+	assert.NoError(t, runner.CatchUp(ctx))
+	status, _ = runner.Status(ctx, "flaky")
+	assert.Equal(t, int64(3), status.Checkpoint)
+}
+
+type countingCheckpointStore struct {
+	CheckpointStore
+	saves int
+}
+
+func (s *countingCheckpointStore) SaveCheckpoint(ctx context.Context, projection string, position int64) error {
+	s.saves++
+	return s.CheckpointStore.SaveCheckpoint(ctx, projection, position)
+}
+
+func TestProjectionCheckpointsOncePerBatch(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	checkpoints := &countingCheckpointStore{CheckpointStore: NewMemoryCheckpointStore()}
+	runner := NewProjectionRunner(store, checkpoints)
+	counts := newOrderCount()
+	assert.NoError(t, runner.Add(counts.projection()))
+
+	names := make([]string, 2*projectionBatchSize+50)
+	for i := range names {
+		names[i] = "item_added"
+	}
+	_, _ = store.Append(ctx, "order-1", NoStream, events(names...)...)
+	assert.NoError(t, runner.Rebuild(ctx, "order_count"))
+
+	assert.Equal(t, len(names), counts.get("order-1"))
+	assert.Equal(t, 4, checkpoints.saves, "O checkpoint deve ser gravado uma vez por lote, além do reset")
+	status, _ := runner.Status(ctx, "order_count")
+	assert.Zero(t, status.Lag)
+}
+
+func TestProjectionAddValidates(t *testing.T) {
+	runner := NewProjectionRunner(NewMemoryStore(), nil)
+	assert.EqualError(t, runner.Add(Projection{}), "projection name cannot be empty")
+	assert.EqualError(t, runner.Add(Projection{Name: "orders"}), "projection orders has no handler")
+	handle := func(ctx context.Context, event *RecordedEvent) error { return nil }
+	assert.NoError(t, runner.Add(Projection{Name: "orders", Handle: handle}))
+	assert.EqualError(t, runner.Add(Projection{Name: "orders", Handle: handle}), "projection orders already registered")
+}
+
+func TestProjectionSubscribesThroughRegistry(t *testing.T) {
+	bus, err := eventbus.New(eventbus.WithBatchSize(1))
+	assert.NoError(t, err)
+	store := NewMemoryStore()
+	runner := NewProjectionRunner(store, nil)
+	counts := newOrderCount()
+	assert.NoError(t, runner.Add(counts.projection()))
+	bus.Import(runner.Registry())
+	bus.Start()
+	defer bus.Stop()
+
+	publishing := NewPublishingStore(store, bus)
+	_, err = publishing.Append(context.Background(), "order-1", NoStream, events("item_added", "item_added")...)
+	assert.NoError(t, err)
+
+	assert.Eventually(t, func() bool { return counts.get("order-1") == 2 }, time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,32 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type PublishingStore struct {
+	Store
+	bus *eventbus.EventBus
+}
+
+func NewPublishingStore(store Store, bus *eventbus.EventBus) *PublishingStore {
+	return &PublishingStore{Store: store, bus: bus}
+}
+
+func (s *PublishingStore) Append(ctx context.Context, streamID string, expectedVersion int64, events ...*eventbus.EventPayload) ([]*RecordedEvent, error) {
+	records, err := s.Store.Append(ctx, streamID, expectedVersion, events...)
+	if err != nil {
+		return nil, err
+	}
+	var errs []error
+	for _, record := range records {
+		if err := s.bus.PublishEnvelope(ctx, copyEnvelope(record.Envelope)); err != nil {
+			errs = append(errs, fmt.Errorf("event %d of stream %s appended but not published: %w", record.Version, streamID, err))
+		}
+	}
+	return records, errors.Join(errs...)
+}
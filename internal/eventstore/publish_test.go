@@ -0,0 +1,48 @@
+package eventstore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPublishingStorePublishesCommittedAppends(t *testing.T) {
+	bus, err := eventbus.New(eventbus.WithBatchSize(1), eventbus.WithEventPriority("order_created", eventbus.PriorityHigh))
+	assert.NoError(t, err)
+	received := make(chan string, 2)
+	bus.Register([]*eventbus.Event{{
+		Name: "order_created",
+		Handler: func(payload interface{}) (interface{}, error) {
+			received <- "order_created"
+			return nil, nil
+		},
+	}})
+	bus.Start()
+	defer bus.Stop()
+
+	memory := NewMemoryStore()
+	store := NewPublishingStore(memory, bus)
+	ctx := context.Background()
+	_, err = store.Append(ctx, "order-1", NoStream, events("order_created")...)
+	assert.NoError(t, err)
+
+	select {
+	case name := <-received:
+		assert.Equal(t, "order_created", name)
+	case <-time.After(time.Second):
+		t.Fatal("Eventos gravados deveriam ser publicados no EventBus")
+	}
+	stored, _ := memory.ReadAll(ctx, 0, Forward, 0)
+	assert.Zero(t, stored[0].Envelope.Priority, "O EventBus não deve alterar o histórico gravado")
+
+	_, err = store.Append(ctx, "order-1", NoStream, events("order_created")...)
+	assert.ErrorIs(t, err, ErrWrongExpectedVersion)
+	select {
+	case <-received:
+		t.Fatal("Appends rejeitados não devem ser publicados")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
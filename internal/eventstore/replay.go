@@ -0,0 +1,31 @@
+package eventstore
+
+import (
+	"context"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type replaySource struct {
+	store Store
+}
+
+func NewReplaySource(store Store) eventbus.ReplaySource {
+	return &replaySource{store: store}
+}
+
+func (s *replaySource) ReadRecords(ctx context.Context, from int64, limit int) ([]*eventbus.ReplayRecord, error) {
+	records, err := s.store.ReadAll(ctx, from, Forward, limit)
+	if err != nil {
+		return nil, err
+	}
+	replayRecords := make([]*eventbus.ReplayRecord, len(records))
+	for i, record := range records {
+		replayRecords[i] = &eventbus.ReplayRecord{
+			Position:   record.Position,
+			RecordedAt: record.RecordedAt,
+			Envelope:   record.Envelope,
+		}
+	}
+	return replayRecords, nil
+}
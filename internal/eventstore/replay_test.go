@@ -0,0 +1,33 @@
+package eventstore
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplaySourceReadsStoreByPosition(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	_, _ = store.Append(ctx, "order-1", NoStream, events("item_added", "order_paid")...)
+	_, _ = store.Append(ctx, "order-2", NoStream, events("item_added")...)
+
+	bus, err := eventbus.New()
+	assert.NoError(t, err)
+	var calls int32
+	bus.Register([]*eventbus.Event{{
+		Name: "item_added",
+		Handler: func(payload interface{}) (interface{}, error) {
+			atomic.AddInt32(&calls, 1)
+			return nil, nil
+		},
+	}})
+
+	result, err := bus.Replay(ctx, NewReplaySource(store), eventbus.ReplayOptions{FromPosition: 2, Names: []string{"item_added"}})
+	assert.NoError(t, err)
+	assert.Equal(t, eventbus.ReplayResult{Scanned: 2, Replayed: 1, Handled: 1}, result)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
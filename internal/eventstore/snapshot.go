@@ -0,0 +1,206 @@
+package eventstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type Snapshot struct {
+	StreamID string    `json:"stream_id"`
+	Version  int64     `json:"version"`
+	State    []byte    `json:"state"`
+	TakenAt  time.Time `json:"taken_at"`
+}
+
+type SnapshotStore interface {
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+	LoadSnapshot(ctx context.Context, streamID string) (*Snapshot, bool, error)
+}
+
+type SnapshotPolicy func(before, after int64) bool
+
+func SnapshotEvery(n int64) SnapshotPolicy {
+	return func(before, after int64) bool {
+		return n > 0 && after/n > before/n
+	}
+}
+
+func SnapshotOnDemand(before, after int64) bool {
+	return false
+}
+
+type MemorySnapshotStore struct {
+	mutex     *sync.RWMutex
+	snapshots map[string]*Snapshot
+}
+
+func NewMemorySnapshotStore() *MemorySnapshotStore {
+	return &MemorySnapshotStore{
+		mutex:     &sync.RWMutex{},
+		snapshots: make(map[string]*Snapshot),
+	}
+}
+
+func (s *MemorySnapshotStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if current, ok := s.snapshots[snapshot.StreamID]; ok && current.Version > snapshot.Version {
+		return nil
+	}
+	s.snapshots[snapshot.StreamID] = snapshot
+	return nil
+}
+
+func (s *MemorySnapshotStore) LoadSnapshot(ctx context.Context, streamID string) (*Snapshot, bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	snapshot, ok := s.snapshots[streamID]
+	return snapshot, ok, nil
+}
+
+type FileSnapshotStore struct {
+	mutex *sync.Mutex
+	dir   string
+}
+
+func NewFileSnapshotStore(dir string) (*FileSnapshotStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create snapshot dir: %w", err)
+	}
+	return &FileSnapshotStore{mutex: &sync.Mutex{}, dir: dir}, nil
+}
+
+func (s *FileSnapshotStore) path(streamID string) string {
+	sum := sha256.Sum256([]byte(streamID))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".snapshot")
+}
+
+func (s *FileSnapshotStore) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	path := s.path(snapshot.StreamID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write snapshot: %w", err)
+	}
+	return nil
+}
+
+func (s *FileSnapshotStore) LoadSnapshot(ctx context.Context, streamID string) (*Snapshot, bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := os.ReadFile(s.path(streamID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, false, fmt.Errorf("failed to decode snapshot: %w", err)
+	}
+	return &snapshot, true, nil
+}
+
+type Aggregate interface {
+	Apply(event *RecordedEvent) error
+}
+
+type Loader struct {
+	store     Store
+	snapshots SnapshotStore
+	policy    SnapshotPolicy
+	codec     eventbus.Codec
+}
+
+func NewLoader(store Store, snapshots SnapshotStore, policy SnapshotPolicy, codec eventbus.Codec) *Loader {
+	if snapshots == nil {
+		snapshots = NewMemorySnapshotStore()
+	}
+	if policy == nil {
+		policy = SnapshotOnDemand
+	}
+	if codec == nil {
+		codec = eventbus.JSONCodec{}
+	}
+	return &Loader{store: store, snapshots: snapshots, policy: policy, codec: codec}
+}
+
+func (l *Loader) Load(ctx context.Context, streamID string, aggregate Aggregate) (int64, error) {
+	var version int64
+	snapshot, ok, err := l.snapshots.LoadSnapshot(ctx, streamID)
+	if err != nil {
+		return 0, err
+	}
+	if ok {
+		if err := l.codec.Unmarshal(snapshot.State, aggregate); err != nil {
+			return 0, fmt.Errorf("failed to decode snapshot of stream %s: %w", streamID, err)
+		}
+		version = snapshot.Version
+	}
+
+	records, err := l.store.ReadStream(ctx, streamID, version+1, Forward, 0)
+	if err != nil {
+		return 0, err
+	}
+	for _, record := range records {
+		if err := aggregate.Apply(record); err != nil {
+			return 0, fmt.Errorf("failed to apply event %d of stream %s: %w", record.Version, streamID, err)
+		}
+		version = record.Version
+	}
+	return version, nil
+}
+
+func (l *Loader) Save(ctx context.Context, streamID string, expectedVersion int64, aggregate Aggregate, events ...*eventbus.EventPayload) ([]*RecordedEvent, error) {
+	records, err := l.store.Append(ctx, streamID, expectedVersion, events...)
+	if err != nil || len(records) == 0 {
+		return records, err
+	}
+	for _, record := range records {
+		if err := aggregate.Apply(record); err != nil {
+			return records, fmt.Errorf("failed to apply event %d of stream %s: %w", record.Version, streamID, err)
+		}
+	}
+	before := records[0].Version - 1
+	after := records[len(records)-1].Version
+	if l.policy(before, after) {
+		if err := l.Snapshot(ctx, streamID, after, aggregate); err != nil {
+			return records, err
+		}
+	}
+	return records, nil
+}
+
+func (l *Loader) Snapshot(ctx context.Context, streamID string, version int64, aggregate Aggregate) error {
+	state, err := l.codec.Marshal(aggregate)
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot of stream %s: %w", streamID, err)
+	}
+	return l.snapshots.SaveSnapshot(ctx, &Snapshot{
+		StreamID: streamID,
+		Version:  version,
+		State:    state,
+		TakenAt:  time.Now().UTC(),
+	})
+}
@@ -0,0 +1,127 @@
+package eventstore
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+type order struct {
+	Items   int    `json:"items"`
+	Status  string `json:"status"`
+	applied int
+}
+
+func (o *order) Apply(event *RecordedEvent) error {
+	o.applied++
+	switch event.Envelope.Name {
+	case "item_added":
+		o.Items++
+	case "order_paid":
+		o.Status = "paid"
+	default:
+		return errors.New("unknown event " + event.Envelope.Name)
+	}
+	return nil
+}
+
+func addItems(t *testing.T, loader *Loader, aggregate *order, version int64, count int) int64 {
+	for i := 0; i < count; i++ {
+		records, err := loader.Save(context.Background(), "order-1", version, aggregate, eventbus.NewEnvelope("item_added", nil))
+		assert.NoError(t, err)
+		version = records[0].Version
+	}
+	return version
+}
+
+func TestSnapshotEveryPolicy(t *testing.T) {
+	policy := SnapshotEvery(3)
+	assert.False(t, policy(0, 2))
+	assert.True(t, policy(2, 3))
+	assert.True(t, policy(1, 7), "Appends que cruzam múltiplos limites devem gerar snapshot")
+	assert.False(t, policy(3, 5))
+	assert.False(t, SnapshotEvery(0)(0, 10))
+}
+
+func TestLoaderReplaysOnlyEventsAfterSnapshot(t *testing.T) {
+	ctx := context.Background()
+	store := NewMemoryStore()
+	snapshots := NewMemorySnapshotStore()
+	loader := NewLoader(store, snapshots, SnapshotEvery(5), nil)
+
+	version := addItems(t, loader, &order{}, NoStream, 7)
+	snapshot, ok, _ := snapshots.LoadSnapshot(ctx, "order-1")
+	if assert.True(t, ok) {
+		assert.Equal(t, int64(5), snapshot.Version)
+	}
+
+	loaded := &order{}
+	loadedVersion, err := loader.Load(ctx, "order-1", loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, version, loadedVersion)
+	assert.Equal(t, 7, loaded.Items)
+	assert.Equal(t, 2, loaded.applied, "Apenas os eventos posteriores ao snapshot devem ser reaplicados")
+}
+
+func TestLoaderSnapshotsOnDemand(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	snapshots, err := NewFileSnapshotStore(dir)
+	assert.NoError(t, err)
+	loader := NewLoader(NewMemoryStore(), snapshots, nil, nil)
+
+	aggregate := &order{}
+	version := addItems(t, loader, aggregate, NoStream, 3)
+	_, ok, _ := snapshots.LoadSnapshot(ctx, "order-1")
+	assert.False(t, ok, "Sem política, snapshots só devem ser gerados sob demanda")
+
+	assert.NoError(t, loader.Snapshot(ctx, "order-1", version, aggregate))
+	_, err = loader.Save(ctx, "order-1", version, aggregate, eventbus.NewEnvelope("order_paid", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "paid", aggregate.Status, "Save deve aplicar os eventos gravados ao agregado")
+
+	reopened, _ := NewFileSnapshotStore(dir)
+	loaded := &order{}
+	loadedVersion, err := NewLoader(loader.store, reopened, nil, nil).Load(ctx, "order-1", loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), loadedVersion)
+	assert.Equal(t, order{Items: 3, Status: "paid", applied: 1}, *loaded)
+}
+
+func TestLoaderWithoutSnapshotReplaysEverything(t *testing.T) {
+	loader := NewLoader(NewMemoryStore(), nil, nil, nil)
+	addItems(t, loader, &order{}, NoStream, 3)
+
+	loaded := &order{}
+	version, err := loader.Load(context.Background(), "order-1", loaded)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	assert.Equal(t, 3, loaded.applied)
+}
+
+func TestLoaderSaveAppliesEventsBeforeSnapshot(t *testing.T) {
+	ctx := context.Background()
+	snapshots := NewMemorySnapshotStore()
+	loader := NewLoader(NewMemoryStore(), snapshots, SnapshotEvery(2), nil)
+
+	aggregate := &order{}
+	_, err := loader.Save(ctx, "order-1", NoStream, aggregate, eventbus.NewEnvelope("item_added", nil), eventbus.NewEnvelope("item_added", nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 2, aggregate.Items)
+
+	snapshot, ok, _ := snapshots.LoadSnapshot(ctx, "order-1")
+	if assert.True(t, ok) {
+		assert.Equal(t, int64(2), snapshot.Version)
+		assert.JSONEq(t, `{"items":2,"status":""}`, string(snapshot.State), "O snapshot deve refletir os eventos gravados")
+	}
+}
+
+func TestLoaderSaveReportsApplyError(t *testing.T) {
+	loader := NewLoader(NewMemoryStore(), nil, SnapshotEvery(1), nil)
+	records, err := loader.Save(context.Background(), "order-1", NoStream, &order{}, eventbus.NewEnvelope("order_cancelled", nil))
+	assert.EqualError(t, err, "failed to apply event 1 of stream order-1: unknown event order_cancelled")
+	assert.Len(t, records, 1, "Os eventos continuam gravados mesmo se o agregado falhar")
+}
@@ -0,0 +1,131 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+const DefaultTable = "eventbus_inbox"
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Inbox struct {
+	table string
+}
+
+type Handler func(ctx context.Context, tx *sql.Tx, envelope *eventbus.EventPayload) (interface{}, error)
+
+type Delivery struct {
+	Tx       *sql.Tx
+	Envelope *eventbus.EventPayload
+	Payload  interface{}
+}
+
+func New(table string) (*Inbox, error) {
+	if table == "" {
+		table = DefaultTable
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid inbox table name %q", table)
+	}
+	return &Inbox{table: table}, nil
+}
+
+func (i *Inbox) CreateTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		consumer TEXT NOT NULL,
+		event_id TEXT NOT NULL,
+		event_name TEXT NOT NULL,
+		processed_at TIMESTAMP NOT NULL,
+		PRIMARY KEY (consumer, event_id)
+	)`, i.table))
+	if err != nil {
+		return fmt.Errorf("failed to create inbox table: %w", err)
+	}
+	return nil
+}
+
+func (i *Inbox) Processed(ctx context.Context, tx *sql.Tx, consumer, eventID string) (bool, error) {
+	var count int
+	err := tx.QueryRowContext(ctx, fmt.Sprintf(`SELECT COUNT(*) FROM %s WHERE consumer = ? AND event_id = ?`, i.table), consumer, eventID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to read inbox: %w", err)
+	}
+	return count > 0, nil
+}
+
+func (i *Inbox) Process(ctx context.Context, tx *sql.Tx, consumer string, envelope *eventbus.EventPayload, handler Handler) (interface{}, bool, error) {
+	if tx == nil {
+		return nil, false, errors.New("inbox requires a transaction")
+	}
+	if envelope.ID == "" {
+		output, err := handler(ctx, tx, envelope)
+		if err != nil {
+			return nil, false, err
+		}
+		return output, true, nil
+	}
+	processed, err := i.Processed(ctx, tx, consumer, envelope.ID)
+	if err != nil || processed {
+		return nil, false, err
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(`INSERT INTO %s (consumer, event_id, event_name, processed_at) VALUES (?, ?, ?, ?)`, i.table),
+		consumer, envelope.ID, envelope.Name, time.Now().UTC(),
+	)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to record event %s in inbox: %w", envelope.ID, err)
+	}
+	output, err := handler(ctx, tx, envelope)
+	if err != nil {
+		return nil, false, err
+	}
+	return output, true, nil
+}
+
+func (i *Inbox) Middleware(db *sql.DB, consumer string, events ...string) eventbus.HandlerMiddleware {
+	names := make(map[string]bool, len(events))
+	for _, name := range events {
+		names[name] = true
+	}
+	return func(next eventbus.HandlerFunc) eventbus.HandlerFunc {
+		return func(ctx context.Context, envelope *eventbus.EventPayload) (interface{}, error) {
+			if len(names) > 0 && !names[envelope.Name] {
+				return next(ctx, envelope)
+			}
+
+			handler := eventbus.HandlerID(ctx)
+			if handler == "" {
+				return nil, fmt.Errorf("inbox middleware requires an ID on every handler of event %s", envelope.Name)
+			}
+			key := consumer + "/" + handler
+			tx, err := db.BeginTx(ctx, nil)
+			if err != nil {
+				return nil, fmt.Errorf("failed to begin inbox transaction: %w", err)
+			}
+			output, processed, err := i.Process(ctx, tx, key, envelope, func(ctx context.Context, tx *sql.Tx, envelope *eventbus.EventPayload) (interface{}, error) {
+				delivery := *envelope
+				delivery.Payload = &Delivery{Tx: tx, Envelope: envelope, Payload: envelope.Payload}
+				return next(ctx, &delivery)
+			})
+			if err != nil || !processed {
+				if rollbackErr := tx.Rollback(); rollbackErr != nil && !errors.Is(rollbackErr, sql.ErrTxDone) {
+					return nil, errors.Join(err, rollbackErr)
+				}
+				if err == nil {
+					err = fmt.Errorf("event %s already processed by %s: %w", envelope.ID, key, eventbus.ErrHandlerSkipped)
+				}
+				return nil, err
+			}
+			if err := tx.Commit(); err != nil {
+				return nil, fmt.Errorf("failed to commit inbox transaction: %w", err)
+			}
+			return output, nil
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package inbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "inbox.db"))
+	assert.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	_, err = db.Exec(`CREATE TABLE charges (order_id TEXT NOT NULL)`)
+	assert.NoError(t, err)
+	return db
+}
+
+func newTestInbox(t *testing.T, db *sql.DB) *Inbox {
+	inbox, err := New("")
+	assert.NoError(t, err)
+	assert.NoError(t, inbox.CreateTable(context.Background(), db))
+	return inbox
+}
+
+func countCharges(t *testing.T, db *sql.DB) int {
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM charges`).Scan(&count))
+	return count
+}
+
+func charge(ctx context.Context, tx *sql.Tx, envelope *eventbus.EventPayload) (interface{}, error) {
+	_, err := tx.ExecContext(ctx, `INSERT INTO charges (order_id) VALUES (?)`, envelope.Payload)
+	return "charged", err
+}
+
+func TestProcessSkipsAlreadyProcessedEvents(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	envelope := eventbus.NewEnvelope("order_paid", "order-1")
+
+	for attempt := 0; attempt < 2; attempt++ {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		output, processed, err := inbox.Process(ctx, tx, "billing", envelope, charge)
+		assert.NoError(t, err)
+		assert.Equal(t, attempt == 0, processed)
+		if processed {
+			assert.Equal(t, "charged", output)
+		}
+		assert.NoError(t, tx.Commit())
+	}
+	assert.Equal(t, 1, countCharges(t, db), "Eventos reentregues não devem repetir efeitos")
+
+	tx, _ := db.BeginTx(ctx, nil)
+	_, processed, err := inbox.Process(ctx, tx, "shipping", envelope, charge)
+	assert.NoError(t, err)
+	assert.True(t, processed, "Cada consumidor mantém sua própria inbox")
+	assert.NoError(t, tx.Commit())
+}
+
+func TestProcessRollbackForgetsEvent(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	envelope := eventbus.NewEnvelope("order_paid", "order-1")
+
+	tx, _ := db.BeginTx(ctx, nil)
+	_, _, err := inbox.Process(ctx, tx, "billing", envelope, func(ctx context.Context, tx *sql.Tx, envelope *eventbus.EventPayload) (interface{}, error) {
+		if _, err := charge(ctx, tx, envelope); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("ledger unavailable")
+	})
+	assert.EqualError(t, err, "ledger unavailable")
+	assert.NoError(t, tx.Rollback())
+	assert.Zero(t, countCharges(t, db))
+
+	tx, _ = db.BeginTx(ctx, nil)
+	processed, err := inbox.Processed(ctx, tx, "billing", envelope.ID)
+	assert.NoError(t, err)
+	assert.False(t, processed, "O marcador deve ser revertido junto com os efeitos do handler")
+	assert.NoError(t, tx.Rollback())
+}
+
+func TestNewRejectsInvalidTableName(t *testing.T) {
+	_, err := New("inbox-table")
+	assert.EqualError(t, err, `invalid inbox table name "inbox-table"`)
+}
+
+type redeliveringBroker struct {
+	envelope  *eventbus.EventPayload
+	delivered bool
+}
+
+func (b *redeliveringBroker) Publish(eventPayload *eventbus.EventPayload, topic string) error {
+	return nil
+}
+
+func (b *redeliveringBroker) Consume(responseQueue chan *eventbus.EventPayload, errorCallback chan error) (*eventbus.EventPayload, error) {
+	if b.delivered {
+		time.Sleep(10 * time.Millisecond)
+		return nil, nil
+	}
+	b.delivered = true
+	responseQueue <- b.envelope
+	responseQueue <- b.envelope
+	return b.envelope, nil
+}
+
+func TestMiddlewareRunsHandlerInTransaction(t *testing.T) {
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	broker := &redeliveringBroker{envelope: eventbus.NewEnvelope("order_paid", "order-1")}
+	eventBus, err := eventbus.New(eventbus.WithBroker(broker))
+	assert.NoError(t, err)
+
+	calls := make(chan struct{}, 2)
+	eventBus.UseHandler(inbox.Middleware(db, "billing", "order_paid"))
+	eventBus.Register([]*eventbus.Event{{
+		ID:   "charge",
+		Name: "order_paid",
+		Handler: func(payload interface{}) (interface{}, error) {
+			defer func() { calls <- struct{}{} }()
+			delivery := payload.(*Delivery)
+			_, err := delivery.Tx.Exec(`INSERT INTO charges (order_id) VALUES (?)`, delivery.Payload)
+			return nil, err
+		},
+	}})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	select {
+	case <-calls:
+	case <-time.After(time.Second):
+		t.Fatal("O handler deveria ter sido executado")
+	}
+	select {
+	case <-calls:
+		t.Fatal("O handler não deve ser executado para eventos já processados")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, 1, countCharges(t, db))
+}
+
+type recordingBroker struct {
+	redeliveringBroker
+	published chan string
+}
+
+func (b *recordingBroker) Publish(eventPayload *eventbus.EventPayload, topic string) error {
+	b.published <- eventPayload.Name
+	return nil
+}
+
+func TestMiddlewareTracksEachHandlerSeparately(t *testing.T) {
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	next := make(chan string, 4)
+	broker := &recordingBroker{redeliveringBroker: redeliveringBroker{envelope: eventbus.NewEnvelope("order_paid", "order-1")}, published: next}
+	eventBus, err := eventbus.New(eventbus.WithBroker(broker), eventbus.WithBatchSize(1))
+	assert.NoError(t, err)
+
+	calls := make(chan string, 4)
+	insert := func(name string) func(payload interface{}) (interface{}, error) {
+		return func(payload interface{}) (interface{}, error) {
+			defer func() { calls <- name }()
+			delivery := payload.(*Delivery)
+			_, err := delivery.Tx.Exec(`INSERT INTO charges (order_id) VALUES (?)`, delivery.Payload)
+			return nil, err
+		}
+	}
+	eventBus.UseHandler(inbox.Middleware(db, "billing", "order_paid"))
+	eventBus.Register([]*eventbus.Event{
+		{ID: "charge", Name: "order_paid", Handler: insert("charge"), Next: &eventbus.Event{Name: "order_charged"}},
+		{ID: "invoice", Name: "order_paid", Handler: insert("invoice")},
+	})
+	eventBus.Start()
+	defer eventBus.Stop()
+
+	handled := map[string]bool{}
+	for len(handled) < 2 {
+		select {
+		case name := <-calls:
+			handled[name] = true
+		case <-time.After(time.Second):
+			t.Fatal("Os dois handlers do evento deveriam ser executados")
+		}
+	}
+	select {
+	case name := <-next:
+		assert.Equal(t, "order_charged", name)
+	case <-time.After(time.Second):
+		t.Fatal("O evento seguinte deveria ser publicado")
+	}
+	select {
+	case name := <-calls:
+		t.Fatalf("O handler %s não deve ser executado para eventos já processados", name)
+	case <-next:
+		t.Fatal("Reentregas não devem publicar o evento seguinte novamente")
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, 2, countCharges(t, db), "Cada handler deve registrar seus efeitos uma vez")
+}
+
+func TestProcessWithoutEventIDSkipsInbox(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	envelope := &eventbus.EventPayload{Name: "order_paid", Payload: "order-1"}
+
+	for attempt := 0; attempt < 2; attempt++ {
+		tx, err := db.BeginTx(ctx, nil)
+		assert.NoError(t, err)
+		_, processed, err := inbox.Process(ctx, tx, "billing", envelope, charge)
+		assert.NoError(t, err)
+		assert.True(t, processed, "Eventos sem ID não devem ser deduplicados")
+		assert.NoError(t, tx.Commit())
+	}
+	assert.Equal(t, 2, countCharges(t, db))
+
+	var recorded int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM `+DefaultTable).Scan(&recorded))
+	assert.Zero(t, recorded, "Eventos sem ID não devem ser gravados na inbox")
+}
+
+func TestMiddlewareRequiresHandlerID(t *testing.T) {
+	db := openTestDB(t)
+	inbox := newTestInbox(t, db)
+	called := false
+	handler := inbox.Middleware(db, "billing")(func(ctx context.Context, envelope *eventbus.EventPayload) (interface{}, error) {
+		called = true
+		return nil, nil
+	})
+
+	_, err := handler(context.Background(), eventbus.NewEnvelope("order_paid", "order-1"))
+	assert.EqualError(t, err, "inbox middleware requires an ID on every handler of event order_paid")
+	assert.False(t, called, "Handlers sem ID não devem executar, pois a chave da inbox não seria estável")
+	assert.Zero(t, countCharges(t, db))
+}
@@ -0,0 +1,34 @@
+package jsonfile
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadMissingFileKeepsValue(t *testing.T) {
+	values := map[string]int{"orders": 1}
+	assert.NoError(t, Load(filepath.Join(t.TempDir(), "missing.json"), "values", &values))
+	assert.Equal(t, map[string]int{"orders": 1}, values, "Um arquivo inexistente não deve alterar o valor")
+}
+
+func TestSaveReplacesFileAtomically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	assert.NoError(t, Save(path, "values", map[string]int{"orders": 1}))
+	assert.NoError(t, Save(path, "values", map[string]int{"orders": 2, "revenue": 3}))
+
+	loaded := make(map[string]int)
+	assert.NoError(t, Load(path, "values", &loaded))
+	assert.Equal(t, map[string]int{"orders": 2, "revenue": 3}, loaded)
+	_, err := os.Stat(path + ".tmp")
+	assert.True(t, os.IsNotExist(err), "O arquivo temporário não deve sobrar após a gravação")
+}
+
+func TestLoadReportsCorruptFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "values.json")
+	assert.NoError(t, os.WriteFile(path, []byte("{"), 0o644))
+	err := Load(path, "values", &map[string]int{})
+	assert.ErrorContains(t, err, "failed to decode values")
+}
@@ -0,0 +1,147 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+const DefaultTable = "eventbus_outbox"
+
+var tableNamePattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type Outbox struct {
+	table string
+	codec eventbus.Codec
+}
+
+type Record struct {
+	Sequence    int64
+	Envelope    *eventbus.EventPayload
+	CreatedAt   time.Time
+	DeliveredAt *time.Time
+}
+
+func New(table string, codec eventbus.Codec) (*Outbox, error) {
+	if table == "" {
+		table = DefaultTable
+	}
+	if !tableNamePattern.MatchString(table) {
+		return nil, fmt.Errorf("invalid outbox table name %q", table)
+	}
+	if codec == nil {
+		codec = eventbus.JSONCodec{}
+	}
+	return &Outbox{table: table, codec: codec}, nil
+}
+
+func (o *Outbox) CreateTable(ctx context.Context, db *sql.DB) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+		sequence INTEGER PRIMARY KEY AUTOINCREMENT,
+		id TEXT NOT NULL UNIQUE,
+		correlation_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		ordering_key TEXT NOT NULL DEFAULT '',
+		priority INTEGER NOT NULL DEFAULT 0,
+		headers BLOB,
+		payload BLOB,
+		created_at TIMESTAMP NOT NULL,
+		delivered_at TIMESTAMP
+	)`, o.table))
+	if err != nil {
+		return fmt.Errorf("failed to create outbox table: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) Publish(ctx context.Context, tx *sql.Tx, name string, payload interface{}, opts ...eventbus.PublishOption) (*eventbus.EventPayload, error) {
+	envelope := eventbus.NewEnvelope(name, payload, opts...)
+	if err := o.Add(ctx, tx, envelope); err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+func (o *Outbox) Add(ctx context.Context, tx *sql.Tx, envelope *eventbus.EventPayload) error {
+	if tx == nil {
+		return errors.New("outbox requires a transaction")
+	}
+	payload, err := o.codec.Marshal(envelope.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode payload: %w", err)
+	}
+	headers, err := json.Marshal(envelope.Headers)
+	if err != nil {
+		return fmt.Errorf("failed to encode headers: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, fmt.Sprintf(
+		`INSERT INTO %s (id, correlation_id, name, ordering_key, priority, headers, payload, created_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`, o.table),
+		envelope.ID, envelope.CorrelationID, envelope.Name, envelope.Key, int(envelope.Priority), headers, payload, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to write event %s to outbox: %w", envelope.Name, err)
+	}
+	return nil
+}
+
+func (o *Outbox) Pending(ctx context.Context, db *sql.DB, limit int) ([]*Record, error) {
+	rows, err := db.QueryContext(ctx, fmt.Sprintf(
+		`SELECT sequence, id, correlation_id, name, ordering_key, priority, headers, payload, created_at FROM %s WHERE delivered_at IS NULL ORDER BY sequence LIMIT ?`, o.table),
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	defer rows.Close()
+
+	var records []*Record
+	for rows.Next() {
+		var (
+			record   Record
+			envelope eventbus.EventPayload
+			priority int
+			headers  []byte
+			payload  []byte
+		)
+		if err := rows.Scan(&record.Sequence, &envelope.ID, &envelope.CorrelationID, &envelope.Name, &envelope.Key, &priority, &headers, &payload, &record.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to read outbox: %w", err)
+		}
+		envelope.Priority = eventbus.Priority(priority)
+		if len(headers) > 0 {
+			if err := json.Unmarshal(headers, &envelope.Headers); err != nil {
+				return nil, fmt.Errorf("failed to decode headers: %w", err)
+			}
+		}
+		if err := o.codec.Unmarshal(payload, &envelope.Payload); err != nil {
+			return nil, fmt.Errorf("failed to decode payload: %w", err)
+		}
+		record.Envelope = &envelope
+		records = append(records, &record)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read outbox: %w", err)
+	}
+	return records, nil
+}
+
+func (o *Outbox) MarkDelivered(ctx context.Context, db *sql.DB, sequence int64) error {
+	_, err := db.ExecContext(ctx, fmt.Sprintf(`UPDATE %s SET delivered_at = ? WHERE sequence = ?`, o.table), time.Now().UTC(), sequence)
+	if err != nil {
+		return fmt.Errorf("failed to mark outbox entry %d delivered: %w", sequence, err)
+	}
+	return nil
+}
+
+func (o *Outbox) Purge(ctx context.Context, db *sql.DB, deliveredBefore time.Time) (int64, error) {
+	result, err := db.ExecContext(ctx, fmt.Sprintf(`DELETE FROM %s WHERE delivered_at IS NOT NULL AND delivered_at < ?`, o.table), deliveredBefore.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge outbox: %w", err)
+	}
+	return result.RowsAffected()
+}
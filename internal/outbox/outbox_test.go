@@ -0,0 +1,110 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+	_ "modernc.org/sqlite"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "outbox.db"))
+	assert.NoError(t, err)
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func newTestOutbox(t *testing.T, db *sql.DB) *Outbox {
+	outbox, err := New("", nil)
+	assert.NoError(t, err)
+	assert.NoError(t, outbox.CreateTable(context.Background(), db))
+	return outbox
+}
+
+func TestNewRejectsInvalidTableName(t *testing.T) {
+	_, err := New("events; DROP TABLE users", nil)
+	assert.EqualError(t, err, `invalid outbox table name "events; DROP TABLE users"`)
+}
+
+func TestPublishIsCommittedWithTransaction(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+	_, err := db.Exec(`CREATE TABLE orders (id TEXT PRIMARY KEY)`)
+	assert.NoError(t, err)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	_, err = tx.Exec(`INSERT INTO orders (id) VALUES ('order-1')`)
+	assert.NoError(t, err)
+	envelope, err := outbox.Publish(ctx, tx, "order_created", map[string]interface{}{"id": "order-1"},
+		eventbus.WithOrderingKey("order-1"), eventbus.WithHeader("tenant", "acme"), eventbus.WithPriority(eventbus.PriorityHigh))
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Commit())
+
+	records, err := outbox.Pending(ctx, db, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, records, 1) {
+		stored := records[0].Envelope
+		assert.Equal(t, envelope.ID, stored.ID)
+		assert.Equal(t, envelope.CorrelationID, stored.CorrelationID)
+		assert.Equal(t, "order_created", stored.Name)
+		assert.Equal(t, "order-1", stored.Key)
+		assert.Equal(t, eventbus.PriorityHigh, stored.Priority)
+		assert.Equal(t, map[string]string{"tenant": "acme"}, stored.Headers)
+		assert.Equal(t, map[string]interface{}{"id": "order-1"}, stored.Payload)
+	}
+}
+
+func TestRolledBackTransactionDiscardsEvent(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+
+	tx, err := db.BeginTx(ctx, nil)
+	assert.NoError(t, err)
+	_, err = outbox.Publish(ctx, tx, "order_created", "order-1")
+	assert.NoError(t, err)
+	assert.NoError(t, tx.Rollback())
+
+	records, err := outbox.Pending(ctx, db, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, records, "Eventos de transações revertidas não devem ser publicados")
+}
+
+func TestPublishRequiresTransaction(t *testing.T) {
+	outbox, _ := New("", nil)
+	_, err := outbox.Publish(context.Background(), nil, "order_created", nil)
+	assert.EqualError(t, err, "outbox requires a transaction")
+}
+
+func TestPurgeRemovesDeliveredEntries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+
+	tx, _ := db.BeginTx(ctx, nil)
+	_, _ = outbox.Publish(ctx, tx, "order_created", "order-1")
+	_, _ = outbox.Publish(ctx, tx, "order_created", "order-2")
+	assert.NoError(t, tx.Commit())
+
+	records, _ := outbox.Pending(ctx, db, 10)
+	assert.NoError(t, outbox.MarkDelivered(ctx, db, records[0].Sequence))
+
+	purged, err := outbox.Purge(ctx, db, time.Now().Add(time.Minute))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), purged)
+
+	var count int
+	assert.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM eventbus_outbox`).Scan(&count))
+	assert.Equal(t, 1, count, "Entradas pendentes não devem ser removidas")
+}
+
+var errBrokerDown = errors.New("broker down")
@@ -0,0 +1,128 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+)
+
+type Relay struct {
+	db           *sql.DB
+	outbox       *Outbox
+	broker       eventbus.EventBroker
+	topic        string
+	batchSize    int
+	pollInterval time.Duration
+	logger       *slog.Logger
+}
+
+type RelayOption func(*Relay) error
+
+func WithTopic(topic string) RelayOption {
+	return func(r *Relay) error {
+		if topic == "" {
+			return errors.New("topic cannot be empty")
+		}
+		r.topic = topic
+		return nil
+	}
+}
+
+func WithBatchSize(size int) RelayOption {
+	return func(r *Relay) error {
+		if size <= 0 {
+			return fmt.Errorf("batch size must be positive, got %d", size)
+		}
+		r.batchSize = size
+		return nil
+	}
+}
+
+func WithPollInterval(interval time.Duration) RelayOption {
+	return func(r *Relay) error {
+		if interval <= 0 {
+			return fmt.Errorf("poll interval must be positive, got %s", interval)
+		}
+		r.pollInterval = interval
+		return nil
+	}
+}
+
+func WithLogger(logger *slog.Logger) RelayOption {
+	return func(r *Relay) error {
+		if logger == nil {
+			return errors.New("logger cannot be nil")
+		}
+		r.logger = logger
+		return nil
+	}
+}
+
+func NewRelay(db *sql.DB, outbox *Outbox, broker eventbus.EventBroker, opts ...RelayOption) (*Relay, error) {
+	if db == nil {
+		return nil, errors.New("database cannot be nil")
+	}
+	if outbox == nil {
+		return nil, errors.New("outbox cannot be nil")
+	}
+	if broker == nil {
+		return nil, errors.New("broker cannot be nil")
+	}
+	relay := &Relay{
+		db:           db,
+		outbox:       outbox,
+		broker:       broker,
+		topic:        eventbus.DefaultTopic,
+		batchSize:    100,
+		pollInterval: time.Second,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		if err := opt(relay); err != nil {
+			return nil, err
+		}
+	}
+	return relay, nil
+}
+
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.pollInterval)
+	defer ticker.Stop()
+	for {
+		for {
+			delivered, err := r.Flush(ctx)
+			if err != nil {
+				r.logger.ErrorContext(ctx, "outbox relay failed", slog.Any("error", err))
+			}
+			if err != nil || delivered < r.batchSize {
+				break
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Relay) Flush(ctx context.Context) (int, error) {
+	records, err := r.outbox.Pending(ctx, r.db, r.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i, record := range records {
+		if err := r.broker.Publish(record.Envelope, r.topic); err != nil {
+			return i, fmt.Errorf("failed to publish event %s: %w", record.Envelope.Name, err)
+		}
+		if err := r.outbox.MarkDelivered(ctx, r.db, record.Sequence); err != nil {
+			return i, err
+		}
+	}
+	return len(records), nil
+}
@@ -0,0 +1,126 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/salesof7/eventbus/internal/eventbus"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordingBroker struct {
+	mutex     sync.Mutex
+	published []*eventbus.EventPayload
+	topics    []string
+	failAfter int
+}
+
+func (b *recordingBroker) Publish(eventPayload *eventbus.EventPayload, topic string) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	if b.failAfter > 0 && len(b.published) >= b.failAfter {
+		return errBrokerDown
+	}
+	b.published = append(b.published, eventPayload)
+	b.topics = append(b.topics, topic)
+	return nil
+}
+
+func (b *recordingBroker) Consume(responseQueue chan *eventbus.EventPayload, errorCallback chan error) (*eventbus.EventPayload, error) {
+	return nil, nil
+}
+
+func (b *recordingBroker) names() []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	names := make([]string, len(b.published))
+	for i, eventPayload := range b.published {
+		names[i] = eventPayload.Payload.(string)
+	}
+	return names
+}
+
+func publishOrders(t *testing.T, db *sql.DB, outbox *Outbox, count int) {
+	tx, err := db.BeginTx(context.Background(), nil)
+	assert.NoError(t, err)
+	for i := 1; i <= count; i++ {
+		_, err := outbox.Publish(context.Background(), tx, "order_created", fmt.Sprintf("order-%d", i))
+		assert.NoError(t, err)
+	}
+	assert.NoError(t, tx.Commit())
+}
+
+func TestRelayForwardsPendingEventsInOrder(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+	broker := &recordingBroker{}
+	relay, err := NewRelay(db, outbox, broker, WithTopic("orders"))
+	assert.NoError(t, err)
+
+	publishOrders(t, db, outbox, 3)
+	delivered, err := relay.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, delivered)
+	assert.Equal(t, []string{"order-1", "order-2", "order-3"}, broker.names())
+	assert.Equal(t, []string{"orders", "orders", "orders"}, broker.topics)
+
+	delivered, err = relay.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Zero(t, delivered, "Eventos entregues não devem ser reenviados")
+}
+
+func TestRelayStopsAtFirstFailureAndRetries(t *testing.T) {
+	ctx := context.Background()
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+	broker := &recordingBroker{failAfter: 2}
+	relay, _ := NewRelay(db, outbox, broker)
+
+	publishOrders(t, db, outbox, 3)
+	delivered, err := relay.Flush(ctx)
+	assert.ErrorIs(t, err, errBrokerDown)
+	assert.Equal(t, 2, delivered)
+
+	pending, _ := outbox.Pending(ctx, db, 10)
+	if assert.Len(t, pending, 1) {
+		assert.Equal(t, "order-3", pending[0].Envelope.Payload)
+	}
+
+	broker.failAfter = 0
+	delivered, err = relay.Flush(ctx)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, delivered)
+	assert.Equal(t, []string{"order-1", "order-2", "order-3"}, broker.names())
+}
+
+func TestRelayRunPollsUntilCancelled(t *testing.T) {
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+	broker := &recordingBroker{}
+	relay, _ := NewRelay(db, outbox, broker, WithPollInterval(10*time.Millisecond), WithBatchSize(2))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- relay.Run(ctx) }()
+
+	publishOrders(t, db, outbox, 5)
+	assert.Eventually(t, func() bool { return len(broker.names()) == 5 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestNewRelayValidatesOptions(t *testing.T) {
+	db := openTestDB(t)
+	outbox := newTestOutbox(t, db)
+
+	_, err := NewRelay(db, outbox, nil)
+	assert.EqualError(t, err, "broker cannot be nil")
+	_, err = NewRelay(db, outbox, &recordingBroker{}, WithBatchSize(0))
+	assert.EqualError(t, err, "batch size must be positive, got 0")
+}